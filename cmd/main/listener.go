@@ -0,0 +1,421 @@
+package main
+
+import (
+	"context"
+	"dearrow-bot/pkg"
+	"dearrow-bot/pkg/config"
+	"dearrow-bot/pkg/db"
+	"dearrow-bot/pkg/dearrow"
+	"dearrow-bot/pkg/handlers"
+	"dearrow-bot/pkg/i18n"
+	"dearrow-bot/pkg/util"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lmittmann/tint"
+	"golang.org/x/sync/errgroup"
+)
+
+// tracked forwards events to the listener as in-flight work, dropping them once the bot is shutting down.
+func tracked(inflight *pkg.Inflight, listener bot.EventListener) bot.EventListener {
+	return bot.NewListenerFunc(func(e bot.Event) {
+		if !inflight.Start() {
+			return
+		}
+		defer inflight.Done()
+		listener.OnEvent(e)
+	})
+}
+
+func messageListener(ctx context.Context, ev *events.GenericGuildMessage, bot *pkg.Bot) {
+	ctx, cancel := context.WithTimeout(ctx, messageTimeout)
+	defer cancel()
+	message := ev.Message
+	if len(message.Embeds) == 0 && !strings.Contains(message.Content, "http") {
+		return
+	}
+	if message.Author.Bot || !canReply(ev) { // ignore bots
+		return
+	}
+	cfg, ok := channelConfig(ctx, ev, bot)
+	if !ok {
+		return
+	}
+	videos := messageVideos(message, cfg, false)
+	if len(videos) == 0 || optedOut(ctx, ev, bot) {
+		return
+	}
+	ok, err := bot.Replies.Reserve(ctx, ev.MessageID)
+	if err != nil {
+		slog.Error("dearrow: error while reserving a reply", slog.Any("parent.id", ev.MessageID), tint.Err(err))
+		return
+	}
+	if !ok { // ignore messages which are being processed or have already been replied to
+		return
+	}
+	createReply(ctx, ev, bot, cfg, videos)
+}
+
+func messageUpdateListener(ctx context.Context, ev *events.GenericGuildMessage, bot *pkg.Bot) {
+	ctx, cancel := context.WithTimeout(ctx, messageTimeout)
+	defer cancel()
+	message := ev.Message
+	suppressed := message.Flags.Has(discord.MessageFlagSuppressEmbeds)
+	if len(message.Embeds) == 0 && !suppressed && !strings.Contains(message.Content, "http") { // messages with a reply always have their embeds suppressed
+		return
+	}
+	if message.Author.Bot || !canReply(ev) { // ignore bots
+		return
+	}
+	cfg, ok := channelConfig(ctx, ev, bot)
+	if !ok {
+		return
+	}
+	if !bot.Replies.Acquire(ev.MessageID) { // ignore messages which are being processed
+		return
+	}
+	reply, ok, err := bot.Replies.Get(ctx, ev.MessageID)
+	if err != nil {
+		slog.Error("dearrow: error while getting a reply", slog.Any("parent.id", ev.MessageID), tint.Err(err))
+		bot.Replies.Release(ev.MessageID)
+		return
+	}
+	if !ok { // e.g. embeds have been resolved after the message was created
+		videos := messageVideos(message, cfg, false)
+		if len(videos) == 0 || optedOut(ctx, ev, bot) {
+			bot.Replies.Release(ev.MessageID)
+			return
+		}
+		createReply(ctx, ev, bot, cfg, videos)
+		return
+	}
+	if message.EditedTimestamp == nil { // not an edit by the author, e.g. embeds being suppressed by us
+		bot.Replies.Release(ev.MessageID)
+		return
+	}
+	updateReply(ctx, ev, bot, cfg, reply)
+}
+
+// channelConfig returns the configuration of the channel the message was sent in, and false if DeArrow is disabled
+// there or the configuration couldn't be fetched.
+func channelConfig(ctx context.Context, ev *events.GenericGuildMessage, bot *pkg.Bot) (config.Guild, bool) {
+	cfg, err := bot.DB.GetGuildConfig(ctx, ev.GuildID)
+	if err != nil {
+		slog.Error("dearrow: error while getting guild config", slog.Any("guild.id", ev.GuildID), tint.Err(err))
+		return cfg, false
+	}
+	chain := util.ChannelChain(ev.Client().Caches, ev.ChannelID)
+	var roleIDs []snowflake.ID
+	if member := ev.Message.Member; member != nil {
+		roleIDs = member.RoleIDs
+	}
+	if !cfg.Allows(chain, roleIDs) {
+		debugLogger.Debug("dearrow: ignoring message excluded by guild config", slog.Any("channel.id", ev.ChannelID), slog.Any("message.id", ev.MessageID))
+		return cfg, false
+	}
+	overrides, err := bot.DB.GetChannelConfigs(ctx, ev.GuildID, chain)
+	if err != nil {
+		slog.Error("dearrow: error while getting channel config", slog.Any("guild.id", ev.GuildID), slog.Any("channel.id", ev.ChannelID), tint.Err(err))
+		return cfg, false
+	}
+	cfg, enabled := config.Resolve(cfg, chain, overrides)
+	if !enabled {
+		debugLogger.Debug("dearrow: ignoring message in disabled channel", slog.Any("channel.id", ev.ChannelID), slog.Any("message.id", ev.MessageID))
+	}
+	return cfg, enabled
+}
+
+// optedOut checks whether the author of the message has opted out of DeArrow replies.
+func optedOut(ctx context.Context, ev *events.GenericGuildMessage, bot *pkg.Bot) bool {
+	userID := ev.Message.Author.ID
+	cfg, err := bot.DB.GetUserConfig(ctx, userID)
+	if err != nil {
+		slog.Error("dearrow: error while getting user preferences", slog.Any("user.id", userID), tint.Err(err))
+		return true
+	}
+	if cfg.OptedOut {
+		debugLogger.Debug("dearrow: ignoring message by opted out user", slog.Any("user.id", userID), slog.Any("message.id", ev.MessageID))
+	}
+	return cfg.OptedOut
+}
+
+// canReply checks whether the bot has all permissions needed to reply to the message and suppress its embeds.
+func canReply(ev *events.GenericGuildMessage) bool {
+	channel, ok := ev.Channel()
+	if !ok {
+		slog.Warn("dearrow: channel missing in cache", slog.Any("channel.id", ev.ChannelID))
+		return false
+	}
+	caches := ev.Client().Caches
+	selfMember, ok := caches.SelfMember(ev.GuildID)
+	if !ok {
+		slog.Warn("dearrow: self member missing in cache", slog.Any("guild.id", ev.GuildID))
+		return false
+	}
+	permissions := caches.MemberPermissionsInChannel(channel, selfMember)
+	debugLogger.Debug("dearrow: permissions in channel", slog.Any("channel.id", ev.ChannelID), slog.Any("permissions", permissions))
+
+	if permissions.Missing(discord.PermissionSendMessages, discord.PermissionManageMessages, discord.PermissionEmbedLinks, discord.PermissionReadMessageHistory) {
+		debugLogger.Debug("dearrow: ignoring message due to missing permissions",
+			slog.Any("channel.id", ev.ChannelID),
+			slog.Any("message.id", ev.MessageID),
+			slog.Any("permissions", permissions))
+		return false
+	}
+	return true
+}
+
+// createReply sends a new DeArrow reply. The parent message must be reserved by the caller.
+func createReply(ctx context.Context, ev *events.GenericGuildMessage, bot *pkg.Bot, cfg config.Guild, videos []video) {
+	data := prepareReply(ctx, ev, bot, cfg, videos)
+	if data == nil || len(data.embeds) == 0 { // no videos to replace, exit
+		bot.Replies.Release(ev.MessageID)
+		return
+	}
+	defer data.close()
+
+	messageCreate := discord.NewMessageCreate()
+	messageCreate = messageCreate.WithMessageReferenceByID(ev.MessageID)
+	messageCreate = messageCreate.WithAllowedMentions(&discord.AllowedMentions{})
+	messageCreate = messageCreate.WithEmbeds(data.embeds...)
+	messageCreate = messageCreate.WithComponents(handlers.ShowOriginalButton(i18n.GuildLocale(ev.Client().Caches, ev.GuildID)))
+	for _, t := range data.thumbnails {
+		messageCreate = messageCreate.AddFile(t.name, "", t.body)
+	}
+
+	reply, err := ev.Client().Rest.CreateMessage(ev.ChannelID, messageCreate, rest.WithCtx(ctx))
+	if err != nil {
+		slog.Error("dearrow: error while sending reply", slog.Any("channel.id", ev.ChannelID), slog.Any("parent.id", ev.MessageID), tint.Err(err))
+		bot.Replies.Release(ev.MessageID)
+		return
+	}
+	commitReply(ctx, ev, bot, db.Reply{ID: reply.ID, VideoIDs: data.videoIDs})
+}
+
+// updateReply brings an existing DeArrow reply in line with the edited parent message. The parent message must be
+// acquired by the caller.
+func updateReply(ctx context.Context, ev *events.GenericGuildMessage, bot *pkg.Bot, cfg config.Guild, reply db.Reply) {
+	videos := messageVideos(ev.Message, cfg, true)
+	if slices.Equal(videoIDs(videos), reply.VideoIDs) { // videos haven't changed
+		bot.Replies.Release(ev.MessageID)
+		return
+	}
+	if len(videos) == 0 {
+		deleteReply(ctx, ev, bot, reply.ID)
+		return
+	}
+
+	data := prepareReply(ctx, ev, bot, cfg, videos)
+	if data == nil {
+		bot.Replies.Release(ev.MessageID)
+		return
+	}
+	if len(data.embeds) == 0 { // none of the remaining videos can be replaced
+		deleteReply(ctx, ev, bot, reply.ID)
+		return
+	}
+	defer data.close()
+
+	messageUpdate := discord.NewMessageUpdate()
+	messageUpdate = messageUpdate.WithEmbeds(data.embeds...)
+	messageUpdate.Attachments = &[]discord.AttachmentUpdate{} // drop old thumbnails, new ones are appended from files
+	for _, t := range data.thumbnails {
+		messageUpdate = messageUpdate.AddFile(t.name, "", t.body)
+	}
+	if _, err := ev.Client().Rest.UpdateMessage(ev.ChannelID, reply.ID, messageUpdate, rest.WithCtx(ctx)); err != nil {
+		slog.Error("dearrow: error while updating reply", slog.Any("channel.id", ev.ChannelID), slog.Any("reply.id", reply.ID), slog.Any("parent.id", ev.MessageID), tint.Err(err))
+		bot.Replies.Release(ev.MessageID)
+		return
+	}
+	commitReply(ctx, ev, bot, db.Reply{ID: reply.ID, VideoIDs: data.videoIDs})
+}
+
+// deleteReply deletes the DeArrow reply as no videos can be replaced anymore.
+func deleteReply(ctx context.Context, ev *events.GenericGuildMessage, bot *pkg.Bot, replyID snowflake.ID) {
+	defer bot.Replies.Release(ev.MessageID)
+	if err := ev.Client().Rest.DeleteMessage(ev.ChannelID, replyID, rest.WithCtx(ctx)); err != nil {
+		slog.Error("dearrow: error while deleting a reply", slog.Any("reply.id", replyID), slog.Any("parent.id", ev.MessageID), tint.Err(err))
+		return
+	}
+	if _, _, err := bot.Replies.Delete(ctx, ev.MessageID); err != nil {
+		slog.Error("dearrow: error while removing a reply", slog.Any("parent.id", ev.MessageID), tint.Err(err))
+	}
+}
+
+// commitReply stores the sent reply and suppresses embeds of the parent message. As the reply has already been sent,
+// this isn't cancelled along with the message.
+func commitReply(ctx context.Context, ev *events.GenericGuildMessage, bot *pkg.Bot, reply db.Reply) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), commitTimeout)
+	defer cancel()
+	client := ev.Client().Rest
+	ok, err := bot.Replies.Commit(ctx, ev.MessageID, ev.ChannelID, reply)
+	if err != nil {
+		slog.Error("dearrow: error while saving a reply", slog.Any("parent.id", ev.MessageID), slog.Any("reply.id", reply.ID), tint.Err(err))
+	}
+	if !ok { // parent has been deleted while the reply was being sent
+		if err := client.DeleteMessage(ev.ChannelID, reply.ID, rest.WithCtx(ctx)); err != nil {
+			slog.Error("dearrow: error while deleting a reply", slog.Any("reply.id", reply.ID), slog.Any("parent.id", ev.MessageID), tint.Err(err))
+		}
+		return
+	}
+	if ev.Message.Flags.Has(discord.MessageFlagSuppressEmbeds) {
+		return
+	}
+	if _, err := client.UpdateMessage(ev.ChannelID, ev.MessageID, discord.MessageUpdate{
+		Flags: new(ev.Message.Flags.Add(discord.MessageFlagSuppressEmbeds)), // add the bit to current flags not to override them
+	}, rest.WithCtx(ctx)); err != nil {
+		slog.Error("dearrow: error while suppressing embeds", slog.Any("channel.id", ev.ChannelID), slog.Any("message.id", ev.MessageID), tint.Err(err))
+	}
+}
+
+// replyData holds the embeds and thumbnails of a DeArrow reply.
+type replyData struct {
+	videoIDs   []string // sorted IDs of all videos in the parent message
+	embeds     []discord.Embed
+	thumbnails []thumbnail
+}
+
+type thumbnail struct {
+	name string
+	body io.ReadCloser
+}
+
+func (d *replyData) close() {
+	for _, t := range d.thumbnails {
+		t.body.Close()
+	}
+}
+
+// video is a YouTube video linked in a message.
+type video struct {
+	id      string
+	url     string         // link as posted, which is kept in the reply
+	embed   *discord.Embed // nil if Discord's embed of the video isn't available
+	spoiler bool
+}
+
+// messageVideos returns all videos which should be replaced in the message. In the embeds mode, videos are taken
+// from Discord's embeds. Once a message has been replied to its embeds are suppressed, so its content is used instead.
+func messageVideos(message discord.Message, cfg config.Guild, replied bool) []video {
+	var videos []video
+	if cfg.LinkMode == config.LinkModeContent || (replied && message.Flags.Has(discord.MessageFlagSuppressEmbeds)) {
+		for _, link := range util.ParseContentLinks(message.Content) {
+			if link.Suppressed && cfg.LinkMode != config.LinkModeContent {
+				continue
+			}
+			v := video{
+				id:      link.VideoID,
+				url:     link.URL,
+				spoiler: link.Spoiler,
+			}
+			if !link.Spoiler && !util.IsFrontendURL(link.URL) {
+				if i := slices.IndexFunc(message.Embeds, func(embed discord.Embed) bool {
+					return util.ParseVideoID(embed) == link.VideoID
+				}); i != -1 {
+					v.embed = &message.Embeds[i]
+				}
+			}
+			videos = append(videos, v)
+		}
+		return videos
+	}
+	for i, embed := range message.Embeds {
+		videoID := util.ParseVideoID(embed)
+		if videoID == "" || slices.ContainsFunc(videos, func(v video) bool { return v.id == videoID }) { // not a YouTube video or a duplicate
+			continue
+		}
+		v := video{
+			id:  videoID,
+			url: embed.URL,
+		}
+		if !util.IsFrontendURL(embed.URL) { // front-end embeds don't carry YouTube's metadata
+			v.embed = &message.Embeds[i]
+		}
+		videos = append(videos, v)
+	}
+	return videos
+}
+
+// videoIDs returns the sorted IDs of the videos.
+func videoIDs(videos []video) []string {
+	ids := make([]string, 0, len(videos))
+	for _, v := range videos {
+		ids = append(ids, v.id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// prepareReply fetches branding and thumbnails for all videos. It returns nil if any request fails.
+func prepareReply(ctx context.Context, ev *events.GenericGuildMessage, bot *pkg.Bot, cfg config.Guild, videos []video) *replyData {
+	data := &replyData{
+		videoIDs: videoIDs(videos),
+	}
+	locale := i18n.GuildLocale(ev.Client().Caches, ev.GuildID)
+	replacementMap := make(map[string]*dearrow.ReplacementData)
+	for _, v := range videos {
+		embed := v.embed
+		if embed == nil { // build the embed ourselves as Discord didn't
+			var err error
+			if embed, err = bot.Client.FetchEmbed(ctx, v.id); err != nil {
+				slog.Error("dearrow: error while fetching a video embed", slog.String("video.id", v.id), tint.Err(err))
+				return nil
+			}
+			embed.URL = v.url
+		}
+		branding := bot.Client.FetchBranding(ctx, v.id, cfg.Locale)
+		if branding == nil {
+			return nil // fail the entire process if any branding request fails for completeness
+		}
+		replacement := branding.ToReplacementData(v.id, cfg, locale, *embed, debugLogger)
+		if replacement != nil && v.spoiler {
+			replacement = replacement.ToSpoiler()
+		}
+		if replacement != nil {
+			replacementMap[v.id] = replacement
+			data.embeds = append(data.embeds, replacement.ToEmbed())
+		}
+	}
+
+	eg, ctx := errgroup.WithContext(ctx)
+	c := make(chan thumbnail, len(replacementMap))
+loop:
+	for videoID, replacement := range replacementMap {
+		select {
+		case <-ctx.Done():
+			break loop
+		default:
+
+		}
+
+		timestamp := replacement.Timestamp
+		if timestamp == -1 { // no need to fetch a new thumbnail
+			continue
+		}
+		eg.Go(func() error {
+			body, err := bot.Client.FetchThumbnail(ctx, videoID, timestamp)
+			if err != nil {
+				return err
+			}
+			c <- thumbnail{name: "thumbnail-" + videoID + ".webp", body: body}
+			return nil
+		})
+	}
+	err := eg.Wait()
+	close(c)
+	for t := range c {
+		data.thumbnails = append(data.thumbnails, t)
+	}
+	if err != nil {
+		data.close()
+		return nil
+	}
+	return data
+}
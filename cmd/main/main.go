@@ -7,10 +7,12 @@ import (
 	"dearrow-bot/pkg/dearrow"
 	"dearrow-bot/pkg/handlers"
 	"dearrow-bot/pkg/util"
-	"io"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -20,31 +22,43 @@ import (
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
 	"github.com/disgoorg/disgo/gateway"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/rest"
 	"github.com/disgoorg/snowflake/v2"
 	"github.com/getsentry/sentry-go"
 	sentryslog "github.com/getsentry/sentry-go/slog"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/lmittmann/tint"
-	"golang.org/x/sync/errgroup"
 )
 
 var (
-	replyMap    = make(map[snowflake.ID]snowflake.ID)
 	debugLogger *slog.Logger
 )
 
 const (
 	cleanPeriod = 24 * time.Hour
+	replyTTL    = 30 * 24 * time.Hour // replies older than this can no longer be cleaned up on parent deletion
+
+	messageTimeout  = 45 * time.Second // leaves enough time for thumbnails to be generated
+	commitTimeout   = 5 * time.Second
+	shutdownTimeout = 20 * time.Second // in-flight work is cancelled if it takes longer to finish on shutdown
+	closeTimeout    = 5 * time.Second
+
+	migrateCommand = "migrate"
 )
 
 func main() {
-	pool, err := pgxpool.New(context.Background(), os.Getenv("DATABASE_URL"))
-	if err != nil {
-		panic(err)
+	// "migrate" only applies pending migrations, e.g. before deploying a new version
+	migrateOnly := len(os.Args) > 1 && os.Args[1] == migrateCommand
+	if len(os.Args) > 1 && !migrateOnly {
+		fmt.Fprintf(os.Stderr, "usage: %s [%s]\n", os.Args[0], migrateCommand)
+		os.Exit(2)
 	}
-	defer pool.Close()
 
-	err = sentry.Init(sentry.ClientOptions{
+	// done once the bot is asked to stop
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	err := sentry.Init(sentry.ClientOptions{
 		Dsn:           os.Getenv("SENTRY_DSN"),
 		EnableTracing: false,
 		BeforeSend: func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
@@ -60,6 +74,13 @@ func main() {
 
 	defer sentry.Flush(2 * time.Second)
 
+	// DEARROW_STORAGE selects the store, DATABASE_URL is the Postgres connection string or the SQLite file path
+	store, err := db.Open(ctx, os.Getenv("DEARROW_STORAGE"), os.Getenv("DATABASE_URL"))
+	if err != nil {
+		panic(err)
+	}
+	defer store.Close() // closed before flushing sentry, after everything else
+
 	fileWriter, err := os.OpenFile("log.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
 		panic(err)
@@ -74,185 +95,166 @@ func main() {
 		sentryslog.Option{LogLevel: []slog.Level{slog.LevelWarn}}.NewSentryHandler(context.Background())))
 	slog.SetDefault(logger)
 
+	if err := store.Migrate(ctx); err != nil {
+		panic(err)
+	}
+	if migrateOnly {
+		slog.Info("database is up to date.")
+		return
+	}
+
 	slog.Info("starting the bot...", slog.String("disgo.version", disgo.Version))
 
+	if hosts := os.Getenv("DEARROW_FRONTEND_HOSTS"); hosts != "" {
+		util.RegisterFrontendHosts(strings.Split(hosts, ",")...)
+	}
+
 	dearrowUserID := snowflake.GetEnv("DEARROW_USER_ID")
 	c := &pkg.Config{
 		DeArrowUserID: dearrowUserID,
 	}
 
+	dearrowClient, err := dearrow.New(util.NewBrandingClient(), util.NewThumbnailClient(),
+		dearrow.WithAPIURLs(os.Getenv("DEARROW_BRANDING_API_URL"), os.Getenv("DEARROW_THUMBNAIL_API_URL")),
+		dearrow.WithBrandingCache( // a size or TTL of 0 disables caching instead of keeping responses forever
+			util.GetEnvInt("DEARROW_BRANDING_CACHE_SIZE", dearrow.DefaultBrandingCacheSize),
+			util.GetEnvDuration("DEARROW_BRANDING_CACHE_TTL", dearrow.DefaultBrandingCacheTTL),
+			util.GetEnvDuration("DEARROW_BRANDING_CACHE_NOT_FOUND_TTL", dearrow.DefaultBrandingCacheNotFoundTTL)),
+		dearrow.WithThumbnailCache(
+			util.GetEnvInt("DEARROW_THUMBNAIL_CACHE_SIZE", dearrow.DefaultThumbnailCacheSize),
+			os.Getenv("DEARROW_THUMBNAIL_CACHE_DIR")),
+		dearrow.WithRetryPolicy(dearrow.RetryPolicy{
+			MaxAttempts: util.GetEnvInt("DEARROW_RETRY_ATTEMPTS", dearrow.DefaultRetryPolicy.MaxAttempts),
+			BaseDelay:   util.GetEnvDuration("DEARROW_RETRY_BASE_DELAY", dearrow.DefaultRetryPolicy.BaseDelay),
+			MaxDelay:    util.GetEnvDuration("DEARROW_RETRY_MAX_DELAY", dearrow.DefaultRetryPolicy.MaxDelay),
+		}),
+		dearrow.WithCircuitBreaker(
+			util.GetEnvInt("DEARROW_BREAKER_THRESHOLD", dearrow.DefaultBreakerThreshold),
+			util.GetEnvDuration("DEARROW_BREAKER_COOLDOWN", dearrow.DefaultBreakerCooldown)))
+	if err != nil {
+		panic(err)
+	}
+
+	// work done for events is only cancelled if it doesn't finish in time on shutdown, background tasks are stopped
+	// once it's done
+	workCtx, cancelWork := context.WithCancel(context.Background())
+	defer cancelWork()
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	var (
+		inflight   pkg.Inflight
+		background sync.WaitGroup
+	)
+
+	if postgres, ok := store.(*db.Postgres); ok {
+		background.Go(func() {
+			postgres.ListenGuildConfigChanges(backgroundCtx)
+		})
+	}
 	b := &pkg.Bot{
-		DB:       db.NewDB(pool),
-		Client:   dearrow.New(util.NewBrandingClient(), util.NewThumbnailClient()),
-		ReplyMap: replyMap,
+		DB:      store,
+		Client:  dearrowClient,
+		Replies: pkg.NewReplyTracker(store),
 	}
-	h := handlers.NewHandler(b, c)
+	h := handlers.NewHandler(workCtx, b, c)
 
 	client, err := disgo.New(os.Getenv("DEARROW_BOT_TOKEN"),
 		bot.WithGatewayConfigOpts(gateway.WithIntents(gateway.IntentGuildMessages, gateway.IntentMessageContent, gateway.IntentGuilds)),
-		bot.WithCacheConfigOpts(cache.WithCaches(cache.FlagChannels, cache.FlagRoles, cache.FlagMembers),
+		bot.WithCacheConfigOpts(cache.WithCaches(cache.FlagGuilds, cache.FlagChannels, cache.FlagRoles, cache.FlagMembers),
 			cache.WithMemberCachePolicy(func(entity discord.Member) bool {
 				return entity.User.ID == dearrowUserID
 			})),
-		bot.WithEventListeners(h, &events.ListenerAdapter{
+		bot.WithEventListeners(tracked(&inflight, h), tracked(&inflight, &events.ListenerAdapter{
 			OnGuildMessageCreate: func(ev *events.GuildMessageCreate) {
-				messageListener(ev.GenericGuildMessage, b)
+				messageListener(workCtx, ev.GenericGuildMessage, b)
 			},
 			OnGuildMessageUpdate: func(ev *events.GuildMessageUpdate) {
 				if time.Since(ev.Message.ID.Time()).Hours() <= 1 { // prevent ghost edits because discord
-					messageListener(ev.GenericGuildMessage, b)
+					messageUpdateListener(workCtx, ev.GenericGuildMessage, b)
 				}
 			},
 			OnGuildMessageDelete: func(ev *events.GuildMessageDelete) {
-				if replyID, ok := replyMap[ev.MessageID]; ok {
-					rest := ev.Client().Rest
-					if err := rest.DeleteMessage(ev.ChannelID, replyID); err != nil {
-						slog.Error("dearrow: error while deleting a reply",
-							slog.Any("reply.id", replyID),
-							slog.Any("parent.id", ev.MessageID),
-							slog.Any("channel.id", ev.ChannelID),
-							tint.Err(err))
-					}
-					delete(replyMap, ev.MessageID)
+				ctx, cancel := context.WithTimeout(workCtx, commitTimeout)
+				defer cancel()
+				replyID, ok, err := b.Replies.Delete(ctx, ev.MessageID)
+				if err != nil {
+					slog.Error("dearrow: error while removing a reply", slog.Any("parent.id", ev.MessageID), tint.Err(err))
+					return
+				}
+				if !ok {
+					return
+				}
+				if err := ev.Client().Rest.DeleteMessage(ev.ChannelID, replyID, rest.WithCtx(ctx)); err != nil {
+					slog.Error("dearrow: error while deleting a reply",
+						slog.Any("reply.id", replyID),
+						slog.Any("parent.id", ev.MessageID),
+						slog.Any("channel.id", ev.ChannelID),
+						tint.Err(err))
 				}
 			},
-		}))
+		})))
 	if err != nil {
 		panic(err)
 	}
 
-	defer client.Close(context.TODO())
-
-	if err := client.OpenGateway(context.TODO()); err != nil {
-		panic(err)
-	}
-
-	ticker := time.NewTicker(cleanPeriod)
-	go func() {
-		for t := range ticker.C {
-			debugLogger.Debug("dearrow: clearing reply map", slog.Time("timestamp", t), slog.Int("count", len(replyMap)))
-			clear(replyMap)
-		}
-	}()
-
-	slog.Info("dearrow bot is now running.")
-	s := make(chan os.Signal, 1)
-	signal.Notify(s, syscall.SIGINT, syscall.SIGTERM, os.Interrupt, os.Kill)
-	<-s
-}
-
-func messageListener(ev *events.GenericGuildMessage, bot *pkg.Bot) {
-	if len(ev.Message.Embeds) == 0 {
-		return
-	}
-	if _, ok := replyMap[ev.MessageID]; ok || ev.Message.Author.Bot { // ignore messages which have already been replied to or bots
-		return
-	}
-	channel, ok := ev.Channel()
-	if !ok {
-		slog.Warn("dearrow: channel missing in cache", slog.Any("channel.id", ev.ChannelID))
-		return
-	}
-	client := ev.Client()
-	caches := client.Caches
-	selfMember, ok := caches.SelfMember(ev.GuildID)
-	if !ok {
-		slog.Warn("dearrow: self member missing in cache", slog.Any("guild.id", ev.GuildID))
-		return
-	}
-	permissions := caches.MemberPermissionsInChannel(channel, selfMember)
-	debugLogger.Debug("dearrow: permissions in channel", slog.Any("channel.id", ev.ChannelID), slog.Any("permissions", permissions))
-
-	if permissions.Missing(discord.PermissionSendMessages, discord.PermissionManageMessages, discord.PermissionEmbedLinks, discord.PermissionReadMessageHistory) {
-		debugLogger.Debug("dearrow: ignoring message due to missing permissions",
-			slog.Any("channel.id", ev.ChannelID),
-			slog.Any("message.id", ev.MessageID),
-			slog.Any("permissions", permissions))
-		return
+	var guildIDs []snowflake.ID
+	if devGuildID := snowflake.GetEnv("DEARROW_DEV_GUILD_ID"); devGuildID != 0 { // sync to a single guild to test changes instantly
+		guildIDs = append(guildIDs, devGuildID)
 	}
-	config, err := bot.DB.GetGuildConfig(ev.GuildID)
-	if err != nil {
-		slog.Error("dearrow: error while getting guild config", slog.Any("guild.id", ev.GuildID), tint.Err(err))
-		return
+	if err := handler.SyncCommands(client, handlers.Commands(), guildIDs); err != nil {
+		slog.Error("dearrow: error while syncing commands", slog.Any("guild.ids", guildIDs), tint.Err(err))
 	}
 
-	replacementMap := make(map[string]*dearrow.ReplacementData)
-	for _, embed := range ev.Message.Embeds {
-		provider := embed.Provider
-		if provider == nil || provider.Name != "YouTube" {
-			continue
-		}
-		videoID := util.ParseVideoID(embed)
-		if videoID == "" {
-			continue
-		}
-		if _, ok := replacementMap[videoID]; ok { // ignore videos that already have a replacement
-			continue
-		}
-		branding := bot.Client.FetchBranding(videoID)
-		if branding == nil {
-			return // fail the entire process if any branding request fails for completeness
-		}
-		data := branding.ToReplacementData(videoID, config, embed, debugLogger)
-		if data != nil {
-			replacementMap[videoID] = data
-		}
-	}
-	if len(replacementMap) == 0 { // no videos to replace, exit
-		return
+	if err := client.OpenGateway(ctx); err != nil {
+		panic(err)
 	}
 
-	messageCreate := discord.NewMessageCreate()
-	messageCreate = messageCreate.WithMessageReferenceByID(ev.MessageID)
-	messageCreate = messageCreate.WithAllowedMentions(&discord.AllowedMentions{})
-
-	eg, ctx := errgroup.WithContext(context.Background())
-	c := make(chan io.ReadCloser, len(replacementMap))
-loop:
-	for videoID, data := range replacementMap {
-		select {
-		case <-ctx.Done():
-			break loop
-		default:
-
-		}
-
-		messageCreate = messageCreate.AddEmbeds(data.ToEmbed())
+	ticker := time.NewTicker(cleanPeriod)
+	defer ticker.Stop()
+	background.Go(func() {
+		for {
+			var t time.Time
+			select {
+			case <-backgroundCtx.Done():
+				return
+			case t = <-ticker.C:
+			}
+			stats := b.Client.BrandingCacheStats()
+			debugLogger.Debug("dearrow: branding cache stats", slog.Uint64("hits", stats.Hits), slog.Uint64("misses", stats.Misses))
+			stats = b.Client.ThumbnailCacheStats()
+			debugLogger.Debug("dearrow: thumbnail cache stats", slog.Uint64("hits", stats.Hits), slog.Uint64("misses", stats.Misses))
 
-		timestamp := data.Timestamp
-		if timestamp == -1 { // no need to fetch a new thumbnail
-			continue
-		}
-		eg.Go(func() error {
-			thumbnail, err := bot.Client.FetchThumbnail(videoID, timestamp)
+			count, err := b.Replies.DeleteExpired(backgroundCtx, t.Add(-replyTTL))
 			if err != nil {
-				return err
+				slog.Error("dearrow: error while removing expired replies", tint.Err(err))
+				continue
 			}
-			c <- thumbnail
-			messageCreate = messageCreate.AddFile("thumbnail-"+videoID+".webp", "", thumbnail)
-			return nil
-		})
-	}
-	if err := eg.Wait(); err != nil {
-		return
-	}
-	close(c)
-
-	reply, err := client.Rest.CreateMessage(ev.ChannelID, messageCreate)
+			debugLogger.Debug("dearrow: removed expired replies", slog.Time("timestamp", t), slog.Int64("count", count))
+		}
+	})
 
-	for closer := range c {
-		closer.Close()
-	}
+	slog.Info("dearrow bot is now running.")
+	<-ctx.Done()
+	stop() // a second signal kills the bot right away
 
-	if err != nil {
-		slog.Error("dearrow: error while sending reply", slog.Any("channel.id", ev.ChannelID), slog.Any("parent.id", ev.MessageID), tint.Err(err))
-		return
+	slog.Info("shutting down the bot...")
+	inflight.Close()
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelDrain()
+	if err := inflight.Wait(drainCtx); err != nil {
+		slog.Warn("dearrow: cancelling in-flight work which didn't finish in time", slog.Duration("timeout", shutdownTimeout))
+		cancelWork()
+		abortCtx, cancelAbort := context.WithTimeout(context.Background(), commitTimeout) // sent replies are still committed
+		defer cancelAbort()
+		if err := inflight.Wait(abortCtx); err != nil {
+			slog.Error("dearrow: error while waiting for cancelled work", tint.Err(err))
+		}
 	}
-	replyMap[ev.MessageID] = reply.ID
+	stopBackground()
+	background.Wait()
 
-	if _, err := client.Rest.UpdateMessage(ev.ChannelID, ev.MessageID, discord.MessageUpdate{
-		Flags: new(ev.Message.Flags.Add(discord.MessageFlagSuppressEmbeds)), // add the bit to current flags not to override them
-	}); err != nil {
-		slog.Error("dearrow: error while suppressing embeds", slog.Any("channel.id", ev.ChannelID), slog.Any("message.id", ev.MessageID), tint.Err(err))
-	}
+	closeCtx, cancelClose := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancelClose()
+	client.Close(closeCtx)
+	slog.Info("dearrow bot has stopped.")
 }
@@ -0,0 +1,32 @@
+package config
+
+// CasualCategory is a reason casual voters can give for the original title and thumbnail being good enough.
+type CasualCategory string
+
+const (
+	CasualCategoryFunny       CasualCategory = "funny"
+	CasualCategoryClever      CasualCategory = "clever"
+	CasualCategoryDescriptive CasualCategory = "descriptive"
+	CasualCategoryOther       CasualCategory = "other"
+)
+
+// CasualCategories are all categories in the order the extension shows them.
+var CasualCategories = []CasualCategory{
+	CasualCategoryFunny,
+	CasualCategoryClever,
+	CasualCategoryDescriptive,
+	CasualCategoryOther,
+}
+
+// DefaultCasualThreshold is the number of votes needed in a category which hasn't been configured, matching the
+// default of the extension.
+const DefaultCasualThreshold = 1
+
+// CasualThreshold returns the number of votes needed in the category for a video to be shown as is in casual mode.
+// 0 means that votes in the category are ignored.
+func (g Guild) CasualThreshold(c CasualCategory) int {
+	if threshold, ok := g.CasualThresholds[string(c)]; ok {
+		return threshold
+	}
+	return DefaultCasualThreshold
+}
@@ -0,0 +1,44 @@
+package config
+
+import "github.com/disgoorg/snowflake/v2"
+
+// Channel holds the overrides of a channel, category or thread. Nil fields inherit the value of the parent
+// channel or the guild.
+type Channel struct {
+	ChannelID         snowflake.ID       `db:"channel_id"`
+	Enabled           *bool              `db:"enabled"`
+	ThumbnailMode     *ThumbnailMode     `db:"thumbnail_mode"`
+	OriginalTitleMode *OriginalTitleMode `db:"title_mode"`
+	Locale            *string            `db:"locale"`
+}
+
+// IsEmpty checks whether the channel doesn't override anything.
+func (c Channel) IsEmpty() bool {
+	return c.Enabled == nil && c.ThumbnailMode == nil && c.OriginalTitleMode == nil && c.Locale == nil
+}
+
+// Resolve applies the overrides of the channel chain, ordered from the most to the least specific channel, to the
+// guild configuration. It returns the resulting configuration and whether DeArrow is enabled in the channel.
+func Resolve(guild Guild, chain []snowflake.ID, overrides []Channel) (Guild, bool) {
+	enabled := true
+	for i := len(chain) - 1; i >= 0; i-- {
+		for _, override := range overrides {
+			if override.ChannelID != chain[i] {
+				continue
+			}
+			if override.Enabled != nil {
+				enabled = *override.Enabled
+			}
+			if override.ThumbnailMode != nil {
+				guild.ThumbnailMode = *override.ThumbnailMode
+			}
+			if override.OriginalTitleMode != nil {
+				guild.OriginalTitleMode = *override.OriginalTitleMode
+			}
+			if override.Locale != nil {
+				guild.Locale = *override.Locale
+			}
+		}
+	}
+	return guild, enabled
+}
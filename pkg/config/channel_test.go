@@ -0,0 +1,80 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+func TestResolve(t *testing.T) {
+	const threadID, channelID, categoryID = snowflake.ID(1), snowflake.ID(2), snowflake.ID(3)
+	chain := []snowflake.ID{threadID, channelID, categoryID}
+	guild := Guild{ThumbnailMode: ThumbnailModeRandomTime, OriginalTitleMode: OriginalTitleModeShown, Locale: "de"}
+
+	tests := []struct {
+		name      string
+		overrides []Channel
+		expected  Guild
+		enabled   bool
+	}{
+		{
+			name:     "no overrides",
+			expected: guild,
+			enabled:  true,
+		},
+		{
+			name: "category",
+			overrides: []Channel{
+				{ChannelID: categoryID, Enabled: new(false), ThumbnailMode: new(ThumbnailModeBlank)},
+			},
+			expected: Guild{ThumbnailMode: ThumbnailModeBlank, OriginalTitleMode: OriginalTitleModeShown, Locale: "de"},
+			enabled:  false,
+		},
+		{
+			name: "channel overrides category",
+			overrides: []Channel{
+				{ChannelID: channelID, Enabled: new(true)},
+				{ChannelID: categoryID, Enabled: new(false), ThumbnailMode: new(ThumbnailModeBlank)},
+			},
+			expected: Guild{ThumbnailMode: ThumbnailModeBlank, OriginalTitleMode: OriginalTitleModeShown, Locale: "de"},
+			enabled:  true,
+		},
+		{
+			name: "thread overrides channel",
+			overrides: []Channel{
+				{ChannelID: channelID, OriginalTitleMode: new(OriginalTitleModeHidden), ThumbnailMode: new(ThumbnailModeBlank)},
+				{ChannelID: threadID, ThumbnailMode: new(ThumbnailModeOriginal)},
+			},
+			expected: Guild{ThumbnailMode: ThumbnailModeOriginal, OriginalTitleMode: OriginalTitleModeHidden, Locale: "de"},
+			enabled:  true,
+		},
+		{
+			name: "default locale in channel",
+			overrides: []Channel{
+				{ChannelID: categoryID, Locale: new("fr")},
+				{ChannelID: channelID, Locale: new("")},
+			},
+			expected: Guild{ThumbnailMode: ThumbnailModeRandomTime, OriginalTitleMode: OriginalTitleModeShown},
+			enabled:  true,
+		},
+		{
+			name: "unrelated channel",
+			overrides: []Channel{
+				{ChannelID: 4, Enabled: new(false)},
+			},
+			expected: guild,
+			enabled:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, enabled := Resolve(guild, chain, tt.overrides)
+			if cfg.ThumbnailMode != tt.expected.ThumbnailMode || cfg.OriginalTitleMode != tt.expected.OriginalTitleMode || cfg.Locale != tt.expected.Locale {
+				t.Errorf("expected %+v, got %+v", tt.expected, cfg)
+			}
+			if enabled != tt.enabled {
+				t.Errorf("expected enabled to be %t, got %t", tt.enabled, enabled)
+			}
+		})
+	}
+}
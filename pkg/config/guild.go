@@ -1,8 +1,92 @@
 package config
 
+import (
+	"slices"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
 type Guild struct {
 	ThumbnailMode     ThumbnailMode     `db:"thumbnail_mode"`
 	OriginalTitleMode OriginalTitleMode `db:"title_mode"`
+	LinkMode          LinkMode          `db:"link_mode"`
+	TitleFormat       TitleFormat       `db:"title_format"`
+	Locale            string            `db:"locale"` // empty for the default submissions
+	Disabled          bool              `db:"disabled"`
+	ChannelAllowlist  []snowflake.ID    `db:"channel_allowlist"`
+	ChannelDenylist   []snowflake.ID    `db:"channel_denylist"`
+	RoleAllowlist     []snowflake.ID    `db:"role_allowlist"`
+	RoleDenylist      []snowflake.ID    `db:"role_denylist"`
+	CasualMode        bool              `db:"casual_mode"`
+	CasualThresholds  map[string]int    `db:"casual_thresholds"` // votes needed per CasualCategory, see CasualThreshold
+
+	// policies, see Policy
+	TitleMinVotes               int  `db:"title_min_votes"`
+	TitleLockedOnly             bool `db:"title_locked_only"`
+	TitleRequireNonOriginal     bool `db:"title_require_non_original"`
+	ThumbnailMinVotes           int  `db:"thumbnail_min_votes"`
+	ThumbnailLockedOnly         bool `db:"thumbnail_locked_only"`
+	ThumbnailRequireNonOriginal bool `db:"thumbnail_require_non_original"`
+}
+
+// Allows checks whether DeArrow may reply in the channel chain, ordered from the most to the least specific channel,
+// to a member with the provided roles. Empty allowlists allow everything, and denylists take precedence.
+func (g Guild) Allows(chain []snowflake.ID, roleIDs []snowflake.ID) bool {
+	if g.Disabled {
+		return false
+	}
+	return allows(g.ChannelAllowlist, g.ChannelDenylist, chain) && allows(g.RoleAllowlist, g.RoleDenylist, roleIDs)
+}
+
+func allows(allowlist []snowflake.ID, denylist []snowflake.ID, ids []snowflake.ID) bool {
+	containsAny := func(list []snowflake.ID) bool {
+		return slices.ContainsFunc(ids, func(id snowflake.ID) bool {
+			return slices.Contains(list, id)
+		})
+	}
+	if containsAny(denylist) {
+		return false
+	}
+	return len(allowlist) == 0 || containsAny(allowlist)
+}
+
+// List is one of the guild's allowlists or denylists.
+type List int
+
+const (
+	ListChannelAllow List = iota
+	ListChannelDeny
+	ListRoleAllow
+	ListRoleDeny
+)
+
+func (l List) String() string {
+	switch l {
+	case ListChannelAllow:
+		return "channel allowlist"
+	case ListChannelDeny:
+		return "channel denylist"
+	case ListRoleAllow:
+		return "role allowlist"
+	case ListRoleDeny:
+		return "role denylist"
+	}
+	return "Unknown"
+}
+
+// Entries returns the IDs in the list.
+func (g Guild) Entries(l List) []snowflake.ID {
+	switch l {
+	case ListChannelAllow:
+		return g.ChannelAllowlist
+	case ListChannelDeny:
+		return g.ChannelDenylist
+	case ListRoleAllow:
+		return g.RoleAllowlist
+	case ListRoleDeny:
+		return g.RoleDenylist
+	}
+	return nil
 }
 
 type ThumbnailMode int
@@ -13,6 +97,13 @@ const (
 	ThumbnailModeOriginal
 )
 
+// ThumbnailModes are all thumbnail modes, e.g. for command choices.
+var ThumbnailModes = []ThumbnailMode{
+	ThumbnailModeRandomTime,
+	ThumbnailModeBlank,
+	ThumbnailModeOriginal,
+}
+
 func (t ThumbnailMode) String() string {
 	switch t {
 	case ThumbnailModeRandomTime:
@@ -32,6 +123,12 @@ const (
 	OriginalTitleModeHidden
 )
 
+// OriginalTitleModes are all original title modes, e.g. for command choices.
+var OriginalTitleModes = []OriginalTitleMode{
+	OriginalTitleModeShown,
+	OriginalTitleModeHidden,
+}
+
 func (t OriginalTitleMode) String() string {
 	switch t {
 	case OriginalTitleModeShown:
@@ -41,3 +138,65 @@ func (t OriginalTitleMode) String() string {
 	}
 	return "Unknown"
 }
+
+type LinkMode int
+
+const (
+	LinkModeEmbeds LinkMode = iota
+	LinkModeContent
+)
+
+// LinkModes are all link modes, e.g. for command choices.
+var LinkModes = []LinkMode{
+	LinkModeEmbeds,
+	LinkModeContent,
+}
+
+func (t LinkMode) String() string {
+	switch t {
+	case LinkModeEmbeds:
+		return "Replace embedded YouTube links"
+	case LinkModeContent:
+		return "Replace all YouTube links, including suppressed and spoilered ones"
+	}
+	return "Unknown"
+}
+
+type TitleFormat int
+
+const (
+	TitleFormatNone TitleFormat = iota
+	TitleFormatCapitalizeWords
+	TitleFormatTitleCase
+	TitleFormatSentenceCase
+	TitleFormatLowerCase
+	TitleFormatFirstLetterUppercase
+)
+
+// TitleFormats are all title formats, e.g. for command choices.
+var TitleFormats = []TitleFormat{
+	TitleFormatNone,
+	TitleFormatCapitalizeWords,
+	TitleFormatTitleCase,
+	TitleFormatSentenceCase,
+	TitleFormatLowerCase,
+	TitleFormatFirstLetterUppercase,
+}
+
+func (t TitleFormat) String() string {
+	switch t {
+	case TitleFormatNone:
+		return "Keep titles as they are"
+	case TitleFormatCapitalizeWords:
+		return "Capitalize Every Word"
+	case TitleFormatTitleCase:
+		return "Title Case"
+	case TitleFormatSentenceCase:
+		return "Sentence case"
+	case TitleFormatLowerCase:
+		return "lower case"
+	case TitleFormatFirstLetterUppercase:
+		return "First letter uppercase"
+	}
+	return "Unknown"
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+func TestGuildAllows(t *testing.T) {
+	chain := []snowflake.ID{1, 2}
+	roles := []snowflake.ID{10, 11}
+
+	tests := []struct {
+		name    string
+		guild   Guild
+		allowed bool
+	}{
+		{"default", Guild{}, true},
+		{"disabled", Guild{Disabled: true}, false},
+		{"allowed channel", Guild{ChannelAllowlist: []snowflake.ID{2}}, true},
+		{"channel not allowed", Guild{ChannelAllowlist: []snowflake.ID{3}}, false},
+		{"denied channel", Guild{ChannelDenylist: []snowflake.ID{1}}, false},
+		{"denied parent channel", Guild{ChannelAllowlist: []snowflake.ID{1}, ChannelDenylist: []snowflake.ID{2}}, false},
+		{"allowed role", Guild{RoleAllowlist: []snowflake.ID{11, 12}}, true},
+		{"role not allowed", Guild{RoleAllowlist: []snowflake.ID{12}}, false},
+		{"denied role", Guild{RoleDenylist: []snowflake.ID{10}}, false},
+		{"allowed channel and denied role", Guild{ChannelAllowlist: []snowflake.ID{1}, RoleDenylist: []snowflake.ID{11}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if allowed := tt.guild.Allows(chain, roles); allowed != tt.allowed {
+				t.Errorf("expected %t, got %t", tt.allowed, allowed)
+			}
+		})
+	}
+}
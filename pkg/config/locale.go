@@ -0,0 +1,13 @@
+package config
+
+import "regexp"
+
+var (
+	localeRegex = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+)
+
+// IsValidLocale checks whether the locale looks like a language tag accepted by the branding API, e.g. "de" or
+// "pt-BR".
+func IsValidLocale(locale string) bool {
+	return localeRegex.MatchString(locale)
+}
@@ -0,0 +1,51 @@
+package config
+
+// Policy restricts which community submissions may replace the original title or thumbnail.
+type Policy struct {
+	MinVotes           int  // submissions with fewer votes are ignored
+	LockedOnly         bool // only submissions locked by a VIP are used
+	RequireNonOriginal bool // submissions voting for the original never replace it, even if locked
+}
+
+// PolicyTarget is the part of the branding a Policy applies to.
+type PolicyTarget int
+
+const (
+	PolicyTargetTitles PolicyTarget = iota
+	PolicyTargetThumbnails
+)
+
+// PolicyTargets are all policy targets, e.g. for command choices.
+var PolicyTargets = []PolicyTarget{
+	PolicyTargetTitles,
+	PolicyTargetThumbnails,
+}
+
+func (t PolicyTarget) String() string {
+	switch t {
+	case PolicyTargetTitles:
+		return "Titles"
+	case PolicyTargetThumbnails:
+		return "Thumbnails"
+	}
+	return "Unknown"
+}
+
+// Policy returns the policy of the target.
+func (g Guild) Policy(t PolicyTarget) Policy {
+	switch t {
+	case PolicyTargetTitles:
+		return Policy{
+			MinVotes:           g.TitleMinVotes,
+			LockedOnly:         g.TitleLockedOnly,
+			RequireNonOriginal: g.TitleRequireNonOriginal,
+		}
+	case PolicyTargetThumbnails:
+		return Policy{
+			MinVotes:           g.ThumbnailMinVotes,
+			LockedOnly:         g.ThumbnailLockedOnly,
+			RequireNonOriginal: g.ThumbnailRequireNonOriginal,
+		}
+	}
+	return Policy{}
+}
@@ -0,0 +1,6 @@
+package config
+
+// User holds the preferences of a user, which apply across all guilds.
+type User struct {
+	OptedOut bool `db:"opted_out"` // whether DeArrow ignores messages sent by the user
+}
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"context"
+	"dearrow-bot/pkg/config"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	selectChannelConfigsQuery = "SELECT channel_id, enabled, thumbnail_mode, title_mode, locale FROM channel_config WHERE guild_id = $1 AND channel_id = ANY($2);"
+	upsertChannelConfigQuery  = "INSERT INTO channel_config (guild_id, channel_id, enabled, thumbnail_mode, title_mode, locale) VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT(channel_id) DO UPDATE SET enabled=COALESCE(excluded.enabled, channel_config.enabled), thumbnail_mode=COALESCE(excluded.thumbnail_mode, channel_config.thumbnail_mode), title_mode=COALESCE(excluded.title_mode, channel_config.title_mode), locale=COALESCE(excluded.locale, channel_config.locale);"
+	deleteChannelConfigQuery  = "DELETE FROM channel_config WHERE guild_id = $1 AND channel_id = $2;"
+)
+
+// GetChannelConfigs returns the overrides of the provided channels. Channels without overrides are omitted.
+func (db *Postgres) GetChannelConfigs(ctx context.Context, guildID snowflake.ID, channelIDs []snowflake.ID) ([]config.Channel, error) {
+	rows, _ := db.pool.Query(ctx, selectChannelConfigsQuery, guildID, channelIDs)
+	return pgx.CollectRows(rows, pgx.RowToStructByName[config.Channel])
+}
+
+// UpdateChannelConfig sets the non-nil overrides of the channel, keeping the existing ones.
+func (db *Postgres) UpdateChannelConfig(ctx context.Context, guildID snowflake.ID, cfg config.Channel) error {
+	_, err := db.pool.Exec(ctx, upsertChannelConfigQuery, guildID, cfg.ChannelID, cfg.Enabled, cfg.ThumbnailMode, cfg.OriginalTitleMode, cfg.Locale)
+	return err
+}
+
+// DeleteChannelConfig removes all overrides of the channel and returns whether there were any.
+func (db *Postgres) DeleteChannelConfig(ctx context.Context, guildID snowflake.ID, channelID snowflake.ID) (bool, error) {
+	tag, err := db.pool.Exec(ctx, deleteChannelConfigQuery, guildID, channelID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() != 0, nil
+}
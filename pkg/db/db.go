@@ -4,6 +4,7 @@ import (
 	"context"
 	"dearrow-bot/pkg/config"
 	"errors"
+	"fmt"
 
 	"github.com/disgoorg/snowflake/v2"
 	"github.com/jackc/pgx/v5"
@@ -11,34 +12,135 @@ import (
 )
 
 const (
-	selectQuery              = "SELECT thumbnail_mode, title_mode FROM config WHERE guild_id = $1;"
-	upsertThumbnailModeQuery = "INSERT INTO config (guild_id, thumbnail_mode) VALUES ($1, $2) ON CONFLICT(guild_id) DO UPDATE SET thumbnail_mode=excluded.thumbnail_mode;"
-	upsertTitleModeQuery     = "INSERT INTO config (guild_id, title_mode) VALUES ($1, $2) ON CONFLICT(guild_id) DO UPDATE SET title_mode=excluded.title_mode;"
+	selectQuery                = "SELECT thumbnail_mode, title_mode, link_mode, title_format, locale, disabled, channel_allowlist, channel_denylist, role_allowlist, role_denylist, casual_mode, casual_thresholds, title_min_votes, title_locked_only, title_require_non_original, thumbnail_min_votes, thumbnail_locked_only, thumbnail_require_non_original FROM config WHERE guild_id = $1;"
+	upsertThumbnailModeQuery   = "INSERT INTO config (guild_id, thumbnail_mode) VALUES ($1, $2) ON CONFLICT(guild_id) DO UPDATE SET thumbnail_mode=excluded.thumbnail_mode;"
+	upsertTitleModeQuery       = "INSERT INTO config (guild_id, title_mode) VALUES ($1, $2) ON CONFLICT(guild_id) DO UPDATE SET title_mode=excluded.title_mode;"
+	upsertLinkModeQuery        = "INSERT INTO config (guild_id, link_mode) VALUES ($1, $2) ON CONFLICT(guild_id) DO UPDATE SET link_mode=excluded.link_mode;"
+	upsertTitleFormatQuery     = "INSERT INTO config (guild_id, title_format) VALUES ($1, $2) ON CONFLICT(guild_id) DO UPDATE SET title_format=excluded.title_format;"
+	upsertLocaleQuery          = "INSERT INTO config (guild_id, locale) VALUES ($1, $2) ON CONFLICT(guild_id) DO UPDATE SET locale=excluded.locale;"
+	upsertDisabledQuery        = "INSERT INTO config (guild_id, disabled) VALUES ($1, $2) ON CONFLICT(guild_id) DO UPDATE SET disabled=excluded.disabled;"
+	upsertCasualModeQuery      = "INSERT INTO config (guild_id, casual_mode) VALUES ($1, $2) ON CONFLICT(guild_id) DO UPDATE SET casual_mode=excluded.casual_mode;"
+	upsertCasualThresholdQuery = "INSERT INTO config (guild_id, casual_thresholds) VALUES ($1, jsonb_build_object($2::text, $3::int)) ON CONFLICT(guild_id) DO UPDATE SET casual_thresholds=COALESCE(config.casual_thresholds, '{}'::jsonb) || excluded.casual_thresholds;"
+	upsertPolicyQuery          = "INSERT INTO config (guild_id, %[1]s_min_votes, %[1]s_locked_only, %[1]s_require_non_original) VALUES ($1, $2, $3, $4) ON CONFLICT(guild_id) DO UPDATE SET %[1]s_min_votes=excluded.%[1]s_min_votes, %[1]s_locked_only=excluded.%[1]s_locked_only, %[1]s_require_non_original=excluded.%[1]s_require_non_original;"
+	addToListQuery             = "INSERT INTO config (guild_id, %[1]s) VALUES ($1, ARRAY[$2::bigint]) ON CONFLICT(guild_id) DO UPDATE SET %[1]s=array_append(array_remove(config.%[1]s, $2::bigint), $2::bigint);"
+	removeFromListQuery        = "UPDATE config SET %[1]s=array_remove(%[1]s, $2::bigint) WHERE guild_id = $1;"
 )
 
-type DB struct {
-	pool *pgxpool.Pool
+var (
+	listColumns = map[config.List]string{
+		config.ListChannelAllow: "channel_allowlist",
+		config.ListChannelDeny:  "channel_denylist",
+		config.ListRoleAllow:    "role_allowlist",
+		config.ListRoleDeny:     "role_denylist",
+	}
+	policyColumnPrefixes = map[config.PolicyTarget]string{
+		config.PolicyTargetTitles:     "title",
+		config.PolicyTargetThumbnails: "thumbnail",
+	}
+)
+
+type Postgres struct {
+	pool   *pgxpool.Pool
+	guilds *guildCache
 }
 
-func NewDB(pool *pgxpool.Pool) *DB {
-	return &DB{pool: pool}
+func NewPostgres(pool *pgxpool.Pool) *Postgres {
+	return &Postgres{
+		pool:   pool,
+		guilds: newGuildCache(),
+	}
 }
 
-func (db *DB) GetGuildConfig(guildID snowflake.ID) (cfg config.Guild, err error) {
-	rows, _ := db.pool.Query(context.Background(), selectQuery, guildID)
+// Close closes the connection pool.
+func (db *Postgres) Close() error {
+	db.pool.Close()
+	return nil
+}
+
+// GetGuildConfig returns the config of the guild, which is cached until it's updated. The returned config must not
+// be modified.
+func (db *Postgres) GetGuildConfig(ctx context.Context, guildID snowflake.ID) (cfg config.Guild, err error) {
+	cfg, epoch, ok := db.guilds.get(guildID)
+	if ok {
+		return
+	}
+	rows, _ := db.pool.Query(ctx, selectQuery, guildID)
 	cfg, err = pgx.CollectOneRow(rows, pgx.RowToStructByName[config.Guild])
 	if err != nil && errors.Is(err, pgx.ErrNoRows) {
 		err = nil
 	}
+	if err == nil {
+		db.guilds.set(guildID, cfg, epoch)
+	}
 	return
 }
 
-func (db *DB) UpdateGuildThumbnailMode(guildID snowflake.ID, mode config.ThumbnailMode) error {
-	_, err := db.pool.Exec(context.Background(), upsertThumbnailModeQuery, guildID, mode)
-	return err
+func (db *Postgres) UpdateGuildThumbnailMode(ctx context.Context, guildID snowflake.ID, mode config.ThumbnailMode) error {
+	return db.updateGuild(ctx, guildID, upsertThumbnailModeQuery, mode)
+}
+
+func (db *Postgres) UpdateGuildTitleMode(ctx context.Context, guildID snowflake.ID, mode config.OriginalTitleMode) error {
+	return db.updateGuild(ctx, guildID, upsertTitleModeQuery, mode)
+}
+
+func (db *Postgres) UpdateGuildLinkMode(ctx context.Context, guildID snowflake.ID, mode config.LinkMode) error {
+	return db.updateGuild(ctx, guildID, upsertLinkModeQuery, mode)
+}
+
+func (db *Postgres) UpdateGuildTitleFormat(ctx context.Context, guildID snowflake.ID, mode config.TitleFormat) error {
+	return db.updateGuild(ctx, guildID, upsertTitleFormatQuery, mode)
+}
+
+func (db *Postgres) UpdateGuildLocale(ctx context.Context, guildID snowflake.ID, locale string) error {
+	return db.updateGuild(ctx, guildID, upsertLocaleQuery, locale)
+}
+
+func (db *Postgres) UpdateGuildDisabled(ctx context.Context, guildID snowflake.ID, disabled bool) error {
+	return db.updateGuild(ctx, guildID, upsertDisabledQuery, disabled)
+}
+
+func (db *Postgres) UpdateGuildCasualMode(ctx context.Context, guildID snowflake.ID, enabled bool) error {
+	return db.updateGuild(ctx, guildID, upsertCasualModeQuery, enabled)
+}
+
+func (db *Postgres) UpdateGuildCasualThreshold(ctx context.Context, guildID snowflake.ID, category config.CasualCategory, votes int) error {
+	return db.updateGuild(ctx, guildID, upsertCasualThresholdQuery, string(category), votes)
+}
+
+func (db *Postgres) UpdateGuildPolicy(ctx context.Context, guildID snowflake.ID, target config.PolicyTarget, policy config.Policy) error {
+	prefix, ok := policyColumnPrefixes[target]
+	if !ok {
+		return fmt.Errorf("unknown policy target: %d", target)
+	}
+	return db.updateGuild(ctx, guildID, fmt.Sprintf(upsertPolicyQuery, prefix), policy.MinVotes, policy.LockedOnly, policy.RequireNonOriginal)
+}
+
+func (db *Postgres) AddToGuildList(ctx context.Context, guildID snowflake.ID, list config.List, id snowflake.ID) error {
+	return db.updateGuildList(ctx, addToListQuery, guildID, list, id)
+}
+
+func (db *Postgres) RemoveFromGuildList(ctx context.Context, guildID snowflake.ID, list config.List, id snowflake.ID) error {
+	return db.updateGuildList(ctx, removeFromListQuery, guildID, list, id)
+}
+
+func (db *Postgres) updateGuildList(ctx context.Context, query string, guildID snowflake.ID, list config.List, id snowflake.ID) error {
+	column, ok := listColumns[list]
+	if !ok {
+		return fmt.Errorf("unknown list: %d", list)
+	}
+	return db.updateGuild(ctx, guildID, fmt.Sprintf(query, column), id)
 }
 
-func (db *DB) UpdateGuildTitleMode(guildID snowflake.ID, mode config.OriginalTitleMode) error {
-	_, err := db.pool.Exec(context.Background(), upsertTitleModeQuery, guildID, mode)
+// updateGuild executes the query updating the config of the guild, whose ID is passed as the first argument, and
+// invalidates the cached config on all instances.
+func (db *Postgres) updateGuild(ctx context.Context, guildID snowflake.ID, query string, args ...any) error {
+	err := pgx.BeginFunc(ctx, db.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, query, append([]any{guildID}, args...)...); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, notifyQuery, guildConfigChannel, guildID.String()) // sent on commit
+		return err
+	})
+	db.guilds.invalidate(guildID)
 	return err
 }
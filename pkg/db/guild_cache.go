@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"dearrow-bot/pkg/config"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lmittmann/tint"
+)
+
+const (
+	// guildCacheTTL bounds how long a config can be stale if an invalidation is missed
+	guildCacheTTL = time.Hour
+	// guildConfigChannel is notified with the guild ID whenever a guild config is updated
+	guildConfigChannel = "guild_config"
+	listenRetryDelay   = 5 * time.Second
+
+	notifyQuery = "SELECT pg_notify($1, $2);"
+	listenQuery = "LISTEN " + guildConfigChannel + ";"
+)
+
+// guildCache holds the configs of all guilds which have been read recently. It is safe for concurrent use.
+type guildCache struct {
+	mu      sync.Mutex
+	epoch   uint64 // incremented on every invalidation, see set
+	entries map[snowflake.ID]guildCacheEntry
+}
+
+type guildCacheEntry struct {
+	cfg       config.Guild
+	expiresAt time.Time
+}
+
+func newGuildCache() *guildCache {
+	return &guildCache{
+		entries: make(map[snowflake.ID]guildCacheEntry),
+	}
+}
+
+// get returns the cached config of the guild and whether it exists, and the epoch to pass to set on a miss.
+func (c *guildCache) get(guildID snowflake.ID) (config.Guild, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[guildID]
+	if ok && time.Now().After(entry.expiresAt) {
+		delete(c.entries, guildID)
+		ok = false
+	}
+	return entry.cfg, c.epoch, ok
+}
+
+// set caches the config read after get returned the epoch. It's dropped if anything has been invalidated since, as
+// the config might have been read before the invalidating update.
+func (c *guildCache) set(guildID snowflake.ID, cfg config.Guild, epoch uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if epoch != c.epoch {
+		return
+	}
+	c.entries[guildID] = guildCacheEntry{
+		cfg:       cfg,
+		expiresAt: time.Now().Add(guildCacheTTL),
+	}
+}
+
+func (c *guildCache) invalidate(guildID snowflake.ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.epoch++
+	delete(c.entries, guildID)
+}
+
+func (c *guildCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.epoch++
+	clear(c.entries)
+}
+
+// ListenGuildConfigChanges invalidates the cached configs of guilds updated by other instances until the context is
+// done. It reconnects on errors, clearing the cache as notifications might have been missed in the meantime.
+func (db *Postgres) ListenGuildConfigChanges(ctx context.Context) {
+	for {
+		err := db.listenGuildConfigChanges(ctx)
+		db.guilds.clear()
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Error("dearrow: error while listening for guild config changes", tint.Err(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+func (db *Postgres) listenGuildConfigChanges(ctx context.Context) error {
+	poolConn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	conn := poolConn.Hijack() // the connection stays subscribed, so it must not return to the pool
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, listenQuery); err != nil {
+		return err
+	}
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		guildID, err := snowflake.Parse(notification.Payload)
+		if err != nil {
+			slog.Error("dearrow: received an invalid guild config notification", slog.String("payload", notification.Payload), tint.Err(err))
+			continue
+		}
+		db.guilds.invalidate(guildID)
+	}
+}
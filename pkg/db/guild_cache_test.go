@@ -0,0 +1,59 @@
+package db
+
+import (
+	"dearrow-bot/pkg/config"
+	"testing"
+	"time"
+)
+
+func TestGuildCache(t *testing.T) {
+	c := newGuildCache()
+	cfg := config.Guild{ThumbnailMode: config.ThumbnailModeBlank}
+
+	_, epoch, ok := c.get(guildID)
+	if ok {
+		t.Fatal("expected a miss on an empty cache")
+	}
+	c.set(guildID, cfg, epoch)
+	if cached, _, ok := c.get(guildID); !ok || cached.ThumbnailMode != cfg.ThumbnailMode {
+		t.Fatalf("expected a hit with %+v, got %+v (%t)", cfg, cached, ok)
+	}
+
+	c.invalidate(guildID)
+	if _, _, ok := c.get(guildID); ok {
+		t.Error("expected a miss after invalidating the guild")
+	}
+
+	c.set(guildID, cfg, epoch)
+	if _, _, ok := c.get(guildID); ok {
+		t.Error("expected a config read before an invalidation not to be cached")
+	}
+}
+
+func TestGuildCacheInvalidateOther(t *testing.T) {
+	c := newGuildCache()
+	_, epoch, _ := c.get(guildID)
+	c.set(guildID, config.Guild{}, epoch)
+
+	c.invalidate(otherGuildID)
+	if _, _, ok := c.get(guildID); !ok {
+		t.Error("expected invalidating another guild to keep the config")
+	}
+	c.clear()
+	if _, _, ok := c.get(guildID); ok {
+		t.Error("expected a miss after clearing the cache")
+	}
+}
+
+func TestGuildCacheExpiry(t *testing.T) {
+	c := newGuildCache()
+	_, epoch, _ := c.get(guildID)
+	c.set(guildID, config.Guild{}, epoch)
+
+	entry := c.entries[guildID]
+	entry.expiresAt = time.Now().Add(-time.Second)
+	c.entries[guildID] = entry
+	if _, _, ok := c.get(guildID); ok {
+		t.Error("expected a miss for an expired config")
+	}
+}
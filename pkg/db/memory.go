@@ -0,0 +1,282 @@
+package db
+
+import (
+	"context"
+	"dearrow-bot/pkg/config"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// Memory is a Store keeping everything in memory, e.g. for tests. It is safe for concurrent use.
+type Memory struct {
+	mu       sync.Mutex
+	guilds   map[snowflake.ID]config.Guild
+	channels map[snowflake.ID]memoryChannel
+	users    map[snowflake.ID]config.User
+	replies  map[snowflake.ID]memoryReply
+}
+
+type memoryChannel struct {
+	guildID snowflake.ID
+	cfg     config.Channel
+}
+
+type memoryReply struct {
+	Reply
+	channelID snowflake.ID
+	createdAt time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		guilds:   make(map[snowflake.ID]config.Guild),
+		channels: make(map[snowflake.ID]memoryChannel),
+		users:    make(map[snowflake.ID]config.User),
+		replies:  make(map[snowflake.ID]memoryReply),
+	}
+}
+
+func (m *Memory) Migrate(context.Context) error {
+	return nil
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
+
+func (m *Memory) GetGuildConfig(_ context.Context, guildID snowflake.ID) (config.Guild, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.guilds[guildID], nil
+}
+
+func (m *Memory) UpdateGuildThumbnailMode(_ context.Context, guildID snowflake.ID, mode config.ThumbnailMode) error {
+	return m.updateGuild(guildID, func(cfg *config.Guild) error {
+		cfg.ThumbnailMode = mode
+		return nil
+	})
+}
+
+func (m *Memory) UpdateGuildTitleMode(_ context.Context, guildID snowflake.ID, mode config.OriginalTitleMode) error {
+	return m.updateGuild(guildID, func(cfg *config.Guild) error {
+		cfg.OriginalTitleMode = mode
+		return nil
+	})
+}
+
+func (m *Memory) UpdateGuildLinkMode(_ context.Context, guildID snowflake.ID, mode config.LinkMode) error {
+	return m.updateGuild(guildID, func(cfg *config.Guild) error {
+		cfg.LinkMode = mode
+		return nil
+	})
+}
+
+func (m *Memory) UpdateGuildTitleFormat(_ context.Context, guildID snowflake.ID, mode config.TitleFormat) error {
+	return m.updateGuild(guildID, func(cfg *config.Guild) error {
+		cfg.TitleFormat = mode
+		return nil
+	})
+}
+
+func (m *Memory) UpdateGuildLocale(_ context.Context, guildID snowflake.ID, locale string) error {
+	return m.updateGuild(guildID, func(cfg *config.Guild) error {
+		cfg.Locale = locale
+		return nil
+	})
+}
+
+func (m *Memory) UpdateGuildDisabled(_ context.Context, guildID snowflake.ID, disabled bool) error {
+	return m.updateGuild(guildID, func(cfg *config.Guild) error {
+		cfg.Disabled = disabled
+		return nil
+	})
+}
+
+func (m *Memory) UpdateGuildCasualMode(_ context.Context, guildID snowflake.ID, enabled bool) error {
+	return m.updateGuild(guildID, func(cfg *config.Guild) error {
+		cfg.CasualMode = enabled
+		return nil
+	})
+}
+
+func (m *Memory) UpdateGuildCasualThreshold(_ context.Context, guildID snowflake.ID, category config.CasualCategory, votes int) error {
+	return m.updateGuild(guildID, func(cfg *config.Guild) error {
+		thresholds := maps.Clone(cfg.CasualThresholds)
+		if thresholds == nil {
+			thresholds = make(map[string]int)
+		}
+		thresholds[string(category)] = votes
+		cfg.CasualThresholds = thresholds
+		return nil
+	})
+}
+
+func (m *Memory) UpdateGuildPolicy(_ context.Context, guildID snowflake.ID, target config.PolicyTarget, policy config.Policy) error {
+	return m.updateGuild(guildID, func(cfg *config.Guild) error {
+		switch target {
+		case config.PolicyTargetTitles:
+			cfg.TitleMinVotes, cfg.TitleLockedOnly, cfg.TitleRequireNonOriginal = policy.MinVotes, policy.LockedOnly, policy.RequireNonOriginal
+		case config.PolicyTargetThumbnails:
+			cfg.ThumbnailMinVotes, cfg.ThumbnailLockedOnly, cfg.ThumbnailRequireNonOriginal = policy.MinVotes, policy.LockedOnly, policy.RequireNonOriginal
+		default:
+			return fmt.Errorf("unknown policy target: %d", target)
+		}
+		return nil
+	})
+}
+
+func (m *Memory) AddToGuildList(_ context.Context, guildID snowflake.ID, list config.List, id snowflake.ID) error {
+	return m.updateGuildList(guildID, list, func(ids []snowflake.ID) []snowflake.ID {
+		return append(removeID(ids, id), id)
+	})
+}
+
+func (m *Memory) RemoveFromGuildList(_ context.Context, guildID snowflake.ID, list config.List, id snowflake.ID) error {
+	return m.updateGuildList(guildID, list, func(ids []snowflake.ID) []snowflake.ID {
+		return removeID(ids, id)
+	})
+}
+
+func (m *Memory) updateGuildList(guildID snowflake.ID, list config.List, update func([]snowflake.ID) []snowflake.ID) error {
+	return m.updateGuild(guildID, func(cfg *config.Guild) error {
+		ids := listField(cfg, list)
+		if ids == nil {
+			return fmt.Errorf("unknown list: %d", list)
+		}
+		*ids = update(*ids)
+		return nil
+	})
+}
+
+// updateGuild applies the update to a copy of the guild config. Slices and maps must be replaced rather than
+// modified, as they're shared with configs returned earlier.
+func (m *Memory) updateGuild(guildID snowflake.ID, update func(*config.Guild) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg := m.guilds[guildID]
+	if err := update(&cfg); err != nil {
+		return err
+	}
+	m.guilds[guildID] = cfg
+	return nil
+}
+
+func (m *Memory) GetChannelConfigs(_ context.Context, guildID snowflake.ID, channelIDs []snowflake.ID) ([]config.Channel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var overrides []config.Channel
+	for _, channelID := range channelIDs {
+		if channel, ok := m.channels[channelID]; ok && channel.guildID == guildID {
+			overrides = append(overrides, channel.cfg)
+		}
+	}
+	return overrides, nil
+}
+
+func (m *Memory) UpdateChannelConfig(_ context.Context, guildID snowflake.ID, cfg config.Channel) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing := m.channels[cfg.ChannelID].cfg
+	if cfg.Enabled == nil {
+		cfg.Enabled = existing.Enabled
+	}
+	if cfg.ThumbnailMode == nil {
+		cfg.ThumbnailMode = existing.ThumbnailMode
+	}
+	if cfg.OriginalTitleMode == nil {
+		cfg.OriginalTitleMode = existing.OriginalTitleMode
+	}
+	if cfg.Locale == nil {
+		cfg.Locale = existing.Locale
+	}
+	m.channels[cfg.ChannelID] = memoryChannel{guildID: guildID, cfg: cfg}
+	return nil
+}
+
+func (m *Memory) DeleteChannelConfig(_ context.Context, guildID snowflake.ID, channelID snowflake.ID) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	channel, ok := m.channels[channelID]
+	if !ok || channel.guildID != guildID {
+		return false, nil
+	}
+	delete(m.channels, channelID)
+	return true, nil
+}
+
+func (m *Memory) GetUserConfig(_ context.Context, userID snowflake.ID) (config.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.users[userID], nil
+}
+
+func (m *Memory) UpdateUserOptedOut(_ context.Context, userID snowflake.ID, optedOut bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg := m.users[userID]
+	cfg.OptedOut = optedOut
+	m.users[userID] = cfg
+	return nil
+}
+
+func (m *Memory) GetReply(_ context.Context, parentID snowflake.ID) (Reply, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	reply, ok := m.replies[parentID]
+	return reply.Reply, ok, nil
+}
+
+func (m *Memory) SaveReply(_ context.Context, parentID snowflake.ID, channelID snowflake.ID, reply Reply) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.replies[parentID] = memoryReply{Reply: reply, channelID: channelID, createdAt: time.Now()}
+	return nil
+}
+
+func (m *Memory) DeleteReply(_ context.Context, parentID snowflake.ID) (snowflake.ID, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	reply, ok := m.replies[parentID]
+	delete(m.replies, parentID)
+	return reply.ID, ok, nil
+}
+
+func (m *Memory) DeleteExpiredReplies(_ context.Context, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var count int64
+	for parentID, reply := range m.replies {
+		if reply.createdAt.Before(before) {
+			delete(m.replies, parentID)
+			count++
+		}
+	}
+	return count, nil
+}
+
+// listField returns a pointer to the list in the config, or nil for unknown lists.
+func listField(cfg *config.Guild, list config.List) *[]snowflake.ID {
+	switch list {
+	case config.ListChannelAllow:
+		return &cfg.ChannelAllowlist
+	case config.ListChannelDeny:
+		return &cfg.ChannelDenylist
+	case config.ListRoleAllow:
+		return &cfg.RoleAllowlist
+	case config.ListRoleDeny:
+		return &cfg.RoleDenylist
+	}
+	return nil
+}
+
+// removeID returns a copy of the IDs without the provided one.
+func removeID(ids []snowflake.ID, id snowflake.ID) []snowflake.ID {
+	return slices.DeleteFunc(slices.Clone(ids), func(other snowflake.ID) bool {
+		return other == id
+	})
+}
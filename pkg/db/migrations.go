@@ -0,0 +1,125 @@
+package db
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lmittmann/tint"
+)
+
+const (
+	// migrationLockID is the key of the advisory lock held while migrating, so only one instance migrates at a time
+	migrationLockID = 0x64656172726f77 // "dearrow"
+
+	createMigrationsTableQuery = "CREATE TABLE IF NOT EXISTS schema_migrations (version integer PRIMARY KEY, name text NOT NULL, applied_at timestamptz NOT NULL DEFAULT now());"
+	selectMigrationsQuery      = "SELECT version FROM schema_migrations;"
+	insertMigrationQuery       = "INSERT INTO schema_migrations (version, name) VALUES ($1, $2);"
+	lockQuery                  = "SELECT pg_advisory_lock($1);"
+	unlockQuery                = "SELECT pg_advisory_unlock($1);"
+
+	postgresMigrations = "migrations/postgres"
+	sqliteMigrations   = "migrations/sqlite"
+)
+
+var (
+	//go:embed migrations
+	migrationFS embed.FS
+)
+
+// migration is a versioned schema change, read from a <version>_<name>.sql file.
+type migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// loadMigrations returns all embedded migrations in the directory ordered by their version.
+func loadMigrations(dir string) ([]migration, error) {
+	files, err := fs.Glob(migrationFS, dir+"/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	migrations := make([]migration, 0, len(files))
+	for _, file := range files {
+		version, name, ok := strings.Cut(strings.TrimSuffix(path.Base(file), ".sql"), "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s is not named <version>_<name>.sql", file)
+		}
+		v, err := strconv.Atoi(version)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has an invalid version: %w", file, err)
+		}
+		b, err := migrationFS.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, migration{Version: v, Name: name, SQL: string(b)})
+	}
+	slices.SortFunc(migrations, func(a, b migration) int {
+		return a.Version - b.Version
+	})
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", migrations[i-1].Name, migrations[i].Name, migrations[i].Version)
+		}
+	}
+	return migrations, nil
+}
+
+// Migrate applies all pending migrations, each in its own transaction. Concurrent calls, e.g. from multiple
+// instances starting at once, wait for each other.
+func (db *Postgres) Migrate(ctx context.Context) error {
+	migrations, err := loadMigrations(postgresMigrations)
+	if err != nil {
+		return err
+	}
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	// advisory locks are held by the session, so lock and unlock on the same connection
+	if _, err := conn.Exec(ctx, lockQuery, migrationLockID); err != nil {
+		return fmt.Errorf("error while acquiring the migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.Exec(context.WithoutCancel(ctx), unlockQuery, migrationLockID); err != nil {
+			slog.Error("dearrow: error while releasing the migration lock", tint.Err(err))
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, createMigrationsTableQuery); err != nil {
+		return err
+	}
+	rows, _ := conn.Query(ctx, selectMigrationsQuery)
+	applied, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if slices.Contains(applied, m.Version) {
+			continue
+		}
+		err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, m.SQL); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, insertMigrationQuery, m.Version, m.Name)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("error while applying migration %d (%s): %w", m.Version, m.Name, err)
+		}
+		slog.Info("dearrow: applied migration", slog.Int("version", m.Version), slog.String("name", m.Name))
+	}
+	return nil
+}
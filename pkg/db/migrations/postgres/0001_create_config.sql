@@ -0,0 +1,7 @@
+-- the table predates the schema files, so it is only created if missing
+CREATE TABLE IF NOT EXISTS config
+(
+    guild_id       bigint PRIMARY KEY,
+    thumbnail_mode integer NOT NULL DEFAULT 0,
+    title_mode     integer NOT NULL DEFAULT 0
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS replies
+(
+    parent_id  bigint PRIMARY KEY,
+    channel_id bigint      NOT NULL,
+    reply_id   bigint      NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS replies_created_at_idx ON replies (created_at);
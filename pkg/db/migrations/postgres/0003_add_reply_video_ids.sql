@@ -0,0 +1,2 @@
+ALTER TABLE replies
+    ADD COLUMN IF NOT EXISTS video_ids text[] NOT NULL DEFAULT '{}';
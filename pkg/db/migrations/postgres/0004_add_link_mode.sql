@@ -0,0 +1,2 @@
+ALTER TABLE config
+    ADD COLUMN IF NOT EXISTS link_mode integer NOT NULL DEFAULT 0;
@@ -0,0 +1,11 @@
+-- null columns inherit the value of the parent channel or the guild
+CREATE TABLE IF NOT EXISTS channel_config
+(
+    channel_id     bigint PRIMARY KEY,
+    guild_id       bigint NOT NULL,
+    enabled        boolean,
+    thumbnail_mode integer,
+    title_mode     integer
+);
+
+CREATE INDEX IF NOT EXISTS channel_config_guild_id_idx ON channel_config (guild_id);
@@ -0,0 +1,6 @@
+ALTER TABLE config
+    ADD COLUMN IF NOT EXISTS disabled          boolean  NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS channel_allowlist bigint[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS channel_denylist  bigint[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS role_allowlist    bigint[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS role_denylist     bigint[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS user_preferences
+(
+    user_id   bigint PRIMARY KEY,
+    opted_out boolean NOT NULL DEFAULT false
+);
@@ -0,0 +1,3 @@
+ALTER TABLE config
+    ADD COLUMN IF NOT EXISTS casual_mode       boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS casual_thresholds jsonb   NOT NULL DEFAULT '{}'::jsonb;
@@ -0,0 +1,7 @@
+ALTER TABLE config
+    ADD COLUMN IF NOT EXISTS title_min_votes                integer NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS title_locked_only              boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS title_require_non_original     boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS thumbnail_min_votes            integer NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS thumbnail_locked_only          boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS thumbnail_require_non_original boolean NOT NULL DEFAULT false;
@@ -0,0 +1,2 @@
+ALTER TABLE config
+    ADD COLUMN IF NOT EXISTS title_format integer NOT NULL DEFAULT 0;
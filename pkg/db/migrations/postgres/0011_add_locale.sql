@@ -0,0 +1,6 @@
+-- an empty guild locale uses the default submissions, a null channel locale inherits it
+ALTER TABLE config
+    ADD COLUMN IF NOT EXISTS locale text NOT NULL DEFAULT '';
+
+ALTER TABLE channel_config
+    ADD COLUMN IF NOT EXISTS locale text;
@@ -0,0 +1,54 @@
+-- lists, thresholds and video IDs are stored as JSON
+CREATE TABLE IF NOT EXISTS config
+(
+    guild_id                       INTEGER PRIMARY KEY,
+    thumbnail_mode                 INTEGER NOT NULL DEFAULT 0,
+    title_mode                     INTEGER NOT NULL DEFAULT 0,
+    link_mode                      INTEGER NOT NULL DEFAULT 0,
+    title_format                   INTEGER NOT NULL DEFAULT 0,
+    locale                         TEXT    NOT NULL DEFAULT '',
+    disabled                       INTEGER NOT NULL DEFAULT 0,
+    channel_allowlist              TEXT    NOT NULL DEFAULT '[]',
+    channel_denylist               TEXT    NOT NULL DEFAULT '[]',
+    role_allowlist                 TEXT    NOT NULL DEFAULT '[]',
+    role_denylist                  TEXT    NOT NULL DEFAULT '[]',
+    casual_mode                    INTEGER NOT NULL DEFAULT 0,
+    casual_thresholds              TEXT    NOT NULL DEFAULT '{}',
+    title_min_votes                INTEGER NOT NULL DEFAULT 0,
+    title_locked_only              INTEGER NOT NULL DEFAULT 0,
+    title_require_non_original     INTEGER NOT NULL DEFAULT 0,
+    thumbnail_min_votes            INTEGER NOT NULL DEFAULT 0,
+    thumbnail_locked_only          INTEGER NOT NULL DEFAULT 0,
+    thumbnail_require_non_original INTEGER NOT NULL DEFAULT 0
+);
+
+-- null columns inherit the value of the parent channel or the guild
+CREATE TABLE IF NOT EXISTS channel_config
+(
+    channel_id     INTEGER PRIMARY KEY,
+    guild_id       INTEGER NOT NULL,
+    enabled        INTEGER,
+    thumbnail_mode INTEGER,
+    title_mode     INTEGER,
+    locale         TEXT
+);
+
+CREATE INDEX IF NOT EXISTS channel_config_guild_id_idx ON channel_config (guild_id);
+
+CREATE TABLE IF NOT EXISTS user_preferences
+(
+    user_id   INTEGER PRIMARY KEY,
+    opted_out INTEGER NOT NULL DEFAULT 0
+);
+
+-- created_at is a unix timestamp in milliseconds
+CREATE TABLE IF NOT EXISTS replies
+(
+    parent_id  INTEGER PRIMARY KEY,
+    channel_id INTEGER NOT NULL,
+    reply_id   INTEGER NOT NULL,
+    video_ids  TEXT    NOT NULL DEFAULT '[]',
+    created_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS replies_created_at_idx ON replies (created_at);
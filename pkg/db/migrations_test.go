@@ -0,0 +1,59 @@
+package db
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var selectColumnsRegex = regexp.MustCompile(`^SELECT (.+?) FROM (\w+)`)
+
+func TestMigrations(t *testing.T) {
+	tests := []struct {
+		dir     string
+		queries []string
+	}{
+		{
+			dir:     postgresMigrations,
+			queries: []string{selectQuery, selectChannelConfigsQuery, selectReplyQuery, selectUserQuery},
+		},
+		{
+			dir:     sqliteMigrations,
+			queries: []string{sqliteSelectGuildQuery, sqliteSelectChannelConfigsQuery, sqliteSelectReplyQuery, sqliteSelectUserQuery},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			migrations, err := loadMigrations(tt.dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var schema strings.Builder
+			for i, m := range migrations {
+				if m.Version != i+1 {
+					t.Errorf("expected migration %s to have version %d, got %d", m.Name, i+1, m.Version)
+				}
+				if strings.TrimSpace(m.SQL) == "" {
+					t.Errorf("migration %s is empty", m.Name)
+				}
+				schema.WriteString(m.SQL)
+			}
+
+			// every selected column has to be created by a migration
+			for _, query := range tt.queries {
+				match := selectColumnsRegex.FindStringSubmatch(query)
+				if match == nil {
+					t.Fatalf("cannot parse query %q", query)
+				}
+				if !strings.Contains(schema.String(), "CREATE TABLE IF NOT EXISTS "+match[2]) {
+					t.Errorf("no migration creates table %s", match[2])
+				}
+				for column := range strings.SplitSeq(match[1], ", ") {
+					if !regexp.MustCompile(`\b` + regexp.QuoteMeta(column) + `\s+\w+`).MatchString(schema.String()) {
+						t.Errorf("no migration creates column %s.%s", match[2], column)
+					}
+				}
+			}
+		})
+	}
+}
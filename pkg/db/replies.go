@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	selectReplyQuery          = "SELECT reply_id, video_ids FROM replies WHERE parent_id = $1;"
+	upsertReplyQuery          = "INSERT INTO replies (parent_id, channel_id, reply_id, video_ids) VALUES ($1, $2, $3, $4) ON CONFLICT(parent_id) DO UPDATE SET channel_id=excluded.channel_id, reply_id=excluded.reply_id, video_ids=excluded.video_ids, created_at=now();"
+	deleteReplyQuery          = "DELETE FROM replies WHERE parent_id = $1 RETURNING reply_id;"
+	deleteExpiredRepliesQuery = "DELETE FROM replies WHERE created_at < $1;"
+)
+
+// Reply is a DeArrow reply to a parent message.
+type Reply struct {
+	ID       snowflake.ID `db:"reply_id"`
+	VideoIDs []string     `db:"video_ids"` // sorted IDs of all videos in the parent message when the reply was last updated
+}
+
+// ReplyStore keeps track of which DeArrow reply belongs to which parent message.
+type ReplyStore interface {
+	// GetReply returns the reply to the parent message and whether it exists.
+	GetReply(ctx context.Context, parentID snowflake.ID) (Reply, bool, error)
+	// SaveReply creates or updates the reply to the parent message. Updated replies count as created anew, so they
+	// expire later.
+	SaveReply(ctx context.Context, parentID snowflake.ID, channelID snowflake.ID, reply Reply) error
+	// DeleteReply removes the reply to the parent message and returns its ID and whether it existed.
+	DeleteReply(ctx context.Context, parentID snowflake.ID) (snowflake.ID, bool, error)
+	// DeleteExpiredReplies removes all replies created or updated before the provided time and returns how many were removed.
+	DeleteExpiredReplies(ctx context.Context, before time.Time) (int64, error)
+}
+
+func (db *Postgres) GetReply(ctx context.Context, parentID snowflake.ID) (reply Reply, ok bool, err error) {
+	rows, _ := db.pool.Query(ctx, selectReplyQuery, parentID)
+	reply, err = pgx.CollectOneRow(rows, pgx.RowToStructByName[Reply])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = nil
+		}
+		return
+	}
+	ok = true
+	return
+}
+
+func (db *Postgres) SaveReply(ctx context.Context, parentID snowflake.ID, channelID snowflake.ID, reply Reply) error {
+	_, err := db.pool.Exec(ctx, upsertReplyQuery, parentID, channelID, reply.ID, reply.VideoIDs)
+	return err
+}
+
+func (db *Postgres) DeleteReply(ctx context.Context, parentID snowflake.ID) (replyID snowflake.ID, ok bool, err error) {
+	err = db.pool.QueryRow(ctx, deleteReplyQuery, parentID).Scan(&replyID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = nil
+		}
+		return
+	}
+	ok = true
+	return
+}
+
+func (db *Postgres) DeleteExpiredReplies(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := db.pool.Exec(ctx, deleteExpiredRepliesQuery, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
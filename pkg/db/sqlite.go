@@ -0,0 +1,357 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"dearrow-bot/pkg/config"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	sqliteSelectGuildQuery           = "SELECT thumbnail_mode, title_mode, link_mode, title_format, locale, disabled, channel_allowlist, channel_denylist, role_allowlist, role_denylist, casual_mode, casual_thresholds, title_min_votes, title_locked_only, title_require_non_original, thumbnail_min_votes, thumbnail_locked_only, thumbnail_require_non_original FROM config WHERE guild_id = ?1;"
+	sqliteUpsertThumbnailModeQuery   = "INSERT INTO config (guild_id, thumbnail_mode) VALUES (?1, ?2) ON CONFLICT(guild_id) DO UPDATE SET thumbnail_mode=excluded.thumbnail_mode;"
+	sqliteUpsertTitleModeQuery       = "INSERT INTO config (guild_id, title_mode) VALUES (?1, ?2) ON CONFLICT(guild_id) DO UPDATE SET title_mode=excluded.title_mode;"
+	sqliteUpsertLinkModeQuery        = "INSERT INTO config (guild_id, link_mode) VALUES (?1, ?2) ON CONFLICT(guild_id) DO UPDATE SET link_mode=excluded.link_mode;"
+	sqliteUpsertTitleFormatQuery     = "INSERT INTO config (guild_id, title_format) VALUES (?1, ?2) ON CONFLICT(guild_id) DO UPDATE SET title_format=excluded.title_format;"
+	sqliteUpsertLocaleQuery          = "INSERT INTO config (guild_id, locale) VALUES (?1, ?2) ON CONFLICT(guild_id) DO UPDATE SET locale=excluded.locale;"
+	sqliteUpsertDisabledQuery        = "INSERT INTO config (guild_id, disabled) VALUES (?1, ?2) ON CONFLICT(guild_id) DO UPDATE SET disabled=excluded.disabled;"
+	sqliteUpsertCasualModeQuery      = "INSERT INTO config (guild_id, casual_mode) VALUES (?1, ?2) ON CONFLICT(guild_id) DO UPDATE SET casual_mode=excluded.casual_mode;"
+	sqliteUpsertCasualThresholdQuery = "INSERT INTO config (guild_id, casual_thresholds) VALUES (?1, json_object(?2, ?3)) ON CONFLICT(guild_id) DO UPDATE SET casual_thresholds=json_set(config.casual_thresholds, '$.' || ?2, ?3);"
+	sqliteUpsertPolicyQuery          = "INSERT INTO config (guild_id, %[1]s_min_votes, %[1]s_locked_only, %[1]s_require_non_original) VALUES (?1, ?2, ?3, ?4) ON CONFLICT(guild_id) DO UPDATE SET %[1]s_min_votes=excluded.%[1]s_min_votes, %[1]s_locked_only=excluded.%[1]s_locked_only, %[1]s_require_non_original=excluded.%[1]s_require_non_original;"
+	sqliteSelectListQuery            = "SELECT %s FROM config WHERE guild_id = ?1;"
+	sqliteUpsertListQuery            = "INSERT INTO config (guild_id, %[1]s) VALUES (?1, ?2) ON CONFLICT(guild_id) DO UPDATE SET %[1]s=excluded.%[1]s;"
+
+	sqliteSelectChannelConfigsQuery = "SELECT channel_id, enabled, thumbnail_mode, title_mode, locale FROM channel_config WHERE guild_id = ?1 AND channel_id IN (SELECT value FROM json_each(?2));"
+	sqliteUpsertChannelConfigQuery  = "INSERT INTO channel_config (guild_id, channel_id, enabled, thumbnail_mode, title_mode, locale) VALUES (?1, ?2, ?3, ?4, ?5, ?6) ON CONFLICT(channel_id) DO UPDATE SET enabled=COALESCE(excluded.enabled, channel_config.enabled), thumbnail_mode=COALESCE(excluded.thumbnail_mode, channel_config.thumbnail_mode), title_mode=COALESCE(excluded.title_mode, channel_config.title_mode), locale=COALESCE(excluded.locale, channel_config.locale);"
+	sqliteDeleteChannelConfigQuery  = "DELETE FROM channel_config WHERE guild_id = ?1 AND channel_id = ?2;"
+
+	sqliteSelectUserQuery         = "SELECT opted_out FROM user_preferences WHERE user_id = ?1;"
+	sqliteUpsertUserOptedOutQuery = "INSERT INTO user_preferences (user_id, opted_out) VALUES (?1, ?2) ON CONFLICT(user_id) DO UPDATE SET opted_out=excluded.opted_out;"
+
+	sqliteSelectReplyQuery          = "SELECT reply_id, video_ids FROM replies WHERE parent_id = ?1;"
+	sqliteUpsertReplyQuery          = "INSERT INTO replies (parent_id, channel_id, reply_id, video_ids, created_at) VALUES (?1, ?2, ?3, ?4, ?5) ON CONFLICT(parent_id) DO UPDATE SET channel_id=excluded.channel_id, reply_id=excluded.reply_id, video_ids=excluded.video_ids, created_at=excluded.created_at;"
+	sqliteDeleteReplyQuery          = "DELETE FROM replies WHERE parent_id = ?1 RETURNING reply_id;"
+	sqliteDeleteExpiredRepliesQuery = "DELETE FROM replies WHERE created_at < ?1;"
+
+	sqliteCreateMigrationsTableQuery = "CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at INTEGER NOT NULL);"
+	sqliteSelectMigrationsQuery      = "SELECT version FROM schema_migrations;"
+	sqliteInsertMigrationQuery       = "INSERT INTO schema_migrations (version, name, applied_at) VALUES (?1, ?2, ?3);"
+)
+
+// SQLite is a Store backed by a single SQLite database file, for running a single instance without Postgres.
+// Lists, casual thresholds and video IDs are stored as JSON.
+type SQLite struct {
+	db *sql.DB
+}
+
+// OpenSQLite opens the database file at the path, creating it if it doesn't exist.
+func OpenSQLite(path string) (*SQLite, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1) // SQLite allows a single writer, so serialize all access instead of failing with SQLITE_BUSY
+	return &SQLite{db: db}, nil
+}
+
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
+
+// Migrate applies all pending migrations, each in its own transaction.
+func (s *SQLite) Migrate(ctx context.Context) error {
+	migrations, err := loadMigrations(sqliteMigrations)
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, sqliteCreateMigrationsTableQuery); err != nil {
+		return err
+	}
+	rows, err := s.db.QueryContext(ctx, sqliteSelectMigrationsQuery)
+	if err != nil {
+		return err
+	}
+	applied, err := collectRows(rows, func(rows *sql.Rows) (version int, err error) {
+		err = rows.Scan(&version)
+		return
+	})
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if slices.Contains(applied, m.Version) {
+			continue
+		}
+		err := s.transaction(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, m.SQL); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, sqliteInsertMigrationQuery, m.Version, m.Name, time.Now().UnixMilli())
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("error while applying migration %d (%s): %w", m.Version, m.Name, err)
+		}
+		slog.Info("dearrow: applied migration", slog.Int("version", m.Version), slog.String("name", m.Name))
+	}
+	return nil
+}
+
+func (s *SQLite) GetGuildConfig(ctx context.Context, guildID snowflake.ID) (cfg config.Guild, err error) {
+	var channelAllowlist, channelDenylist, roleAllowlist, roleDenylist, casualThresholds string
+	err = s.db.QueryRowContext(ctx, sqliteSelectGuildQuery, guildID).Scan(
+		&cfg.ThumbnailMode, &cfg.OriginalTitleMode, &cfg.LinkMode, &cfg.TitleFormat, &cfg.Locale, &cfg.Disabled,
+		&channelAllowlist, &channelDenylist, &roleAllowlist, &roleDenylist,
+		&cfg.CasualMode, &casualThresholds,
+		&cfg.TitleMinVotes, &cfg.TitleLockedOnly, &cfg.TitleRequireNonOriginal,
+		&cfg.ThumbnailMinVotes, &cfg.ThumbnailLockedOnly, &cfg.ThumbnailRequireNonOriginal)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil
+		}
+		return
+	}
+	for raw, ids := range map[string]*[]snowflake.ID{
+		channelAllowlist: &cfg.ChannelAllowlist,
+		channelDenylist:  &cfg.ChannelDenylist,
+		roleAllowlist:    &cfg.RoleAllowlist,
+		roleDenylist:     &cfg.RoleDenylist,
+	} {
+		if *ids, err = unmarshalIDs(raw); err != nil {
+			return
+		}
+	}
+	err = json.Unmarshal([]byte(casualThresholds), &cfg.CasualThresholds)
+	return
+}
+
+func (s *SQLite) UpdateGuildThumbnailMode(ctx context.Context, guildID snowflake.ID, mode config.ThumbnailMode) error {
+	_, err := s.db.ExecContext(ctx, sqliteUpsertThumbnailModeQuery, guildID, mode)
+	return err
+}
+
+func (s *SQLite) UpdateGuildTitleMode(ctx context.Context, guildID snowflake.ID, mode config.OriginalTitleMode) error {
+	_, err := s.db.ExecContext(ctx, sqliteUpsertTitleModeQuery, guildID, mode)
+	return err
+}
+
+func (s *SQLite) UpdateGuildLinkMode(ctx context.Context, guildID snowflake.ID, mode config.LinkMode) error {
+	_, err := s.db.ExecContext(ctx, sqliteUpsertLinkModeQuery, guildID, mode)
+	return err
+}
+
+func (s *SQLite) UpdateGuildTitleFormat(ctx context.Context, guildID snowflake.ID, mode config.TitleFormat) error {
+	_, err := s.db.ExecContext(ctx, sqliteUpsertTitleFormatQuery, guildID, mode)
+	return err
+}
+
+func (s *SQLite) UpdateGuildLocale(ctx context.Context, guildID snowflake.ID, locale string) error {
+	_, err := s.db.ExecContext(ctx, sqliteUpsertLocaleQuery, guildID, locale)
+	return err
+}
+
+func (s *SQLite) UpdateGuildDisabled(ctx context.Context, guildID snowflake.ID, disabled bool) error {
+	_, err := s.db.ExecContext(ctx, sqliteUpsertDisabledQuery, guildID, disabled)
+	return err
+}
+
+func (s *SQLite) UpdateGuildCasualMode(ctx context.Context, guildID snowflake.ID, enabled bool) error {
+	_, err := s.db.ExecContext(ctx, sqliteUpsertCasualModeQuery, guildID, enabled)
+	return err
+}
+
+func (s *SQLite) UpdateGuildCasualThreshold(ctx context.Context, guildID snowflake.ID, category config.CasualCategory, votes int) error {
+	_, err := s.db.ExecContext(ctx, sqliteUpsertCasualThresholdQuery, guildID, string(category), votes)
+	return err
+}
+
+func (s *SQLite) UpdateGuildPolicy(ctx context.Context, guildID snowflake.ID, target config.PolicyTarget, policy config.Policy) error {
+	prefix, ok := policyColumnPrefixes[target]
+	if !ok {
+		return fmt.Errorf("unknown policy target: %d", target)
+	}
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(sqliteUpsertPolicyQuery, prefix), guildID, policy.MinVotes, policy.LockedOnly, policy.RequireNonOriginal)
+	return err
+}
+
+func (s *SQLite) AddToGuildList(ctx context.Context, guildID snowflake.ID, list config.List, id snowflake.ID) error {
+	return s.updateGuildList(ctx, guildID, list, func(ids []snowflake.ID) []snowflake.ID {
+		return append(removeID(ids, id), id)
+	})
+}
+
+func (s *SQLite) RemoveFromGuildList(ctx context.Context, guildID snowflake.ID, list config.List, id snowflake.ID) error {
+	return s.updateGuildList(ctx, guildID, list, func(ids []snowflake.ID) []snowflake.ID {
+		return removeID(ids, id)
+	})
+}
+
+func (s *SQLite) updateGuildList(ctx context.Context, guildID snowflake.ID, list config.List, update func([]snowflake.ID) []snowflake.ID) error {
+	column, ok := listColumns[list]
+	if !ok {
+		return fmt.Errorf("unknown list: %d", list)
+	}
+	return s.transaction(ctx, func(tx *sql.Tx) error {
+		raw := "[]"
+		err := tx.QueryRowContext(ctx, fmt.Sprintf(sqliteSelectListQuery, column), guildID).Scan(&raw)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		ids, err := unmarshalIDs(raw)
+		if err != nil {
+			return err
+		}
+		raw, err = marshalIDs(update(ids))
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(sqliteUpsertListQuery, column), guildID, raw)
+		return err
+	})
+}
+
+func (s *SQLite) GetChannelConfigs(ctx context.Context, guildID snowflake.ID, channelIDs []snowflake.ID) ([]config.Channel, error) {
+	ids, err := marshalIDs(channelIDs)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, sqliteSelectChannelConfigsQuery, guildID, ids)
+	if err != nil {
+		return nil, err
+	}
+	return collectRows(rows, func(rows *sql.Rows) (cfg config.Channel, err error) {
+		err = rows.Scan(&cfg.ChannelID, &cfg.Enabled, &cfg.ThumbnailMode, &cfg.OriginalTitleMode, &cfg.Locale)
+		return
+	})
+}
+
+func (s *SQLite) UpdateChannelConfig(ctx context.Context, guildID snowflake.ID, cfg config.Channel) error {
+	_, err := s.db.ExecContext(ctx, sqliteUpsertChannelConfigQuery, guildID, cfg.ChannelID, cfg.Enabled, cfg.ThumbnailMode, cfg.OriginalTitleMode, cfg.Locale)
+	return err
+}
+
+func (s *SQLite) DeleteChannelConfig(ctx context.Context, guildID snowflake.ID, channelID snowflake.ID) (bool, error) {
+	result, err := s.db.ExecContext(ctx, sqliteDeleteChannelConfigQuery, guildID, channelID)
+	if err != nil {
+		return false, err
+	}
+	count, err := result.RowsAffected()
+	return count != 0, err
+}
+
+func (s *SQLite) GetUserConfig(ctx context.Context, userID snowflake.ID) (cfg config.User, err error) {
+	err = s.db.QueryRowContext(ctx, sqliteSelectUserQuery, userID).Scan(&cfg.OptedOut)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	return
+}
+
+func (s *SQLite) UpdateUserOptedOut(ctx context.Context, userID snowflake.ID, optedOut bool) error {
+	_, err := s.db.ExecContext(ctx, sqliteUpsertUserOptedOutQuery, userID, optedOut)
+	return err
+}
+
+func (s *SQLite) GetReply(ctx context.Context, parentID snowflake.ID) (reply Reply, ok bool, err error) {
+	var videoIDs string
+	err = s.db.QueryRowContext(ctx, sqliteSelectReplyQuery, parentID).Scan(&reply.ID, &videoIDs)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil
+		}
+		return
+	}
+	if err = json.Unmarshal([]byte(videoIDs), &reply.VideoIDs); err != nil {
+		return
+	}
+	ok = true
+	return
+}
+
+func (s *SQLite) SaveReply(ctx context.Context, parentID snowflake.ID, channelID snowflake.ID, reply Reply) error {
+	videoIDs, err := json.Marshal(reply.VideoIDs)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, sqliteUpsertReplyQuery, parentID, channelID, reply.ID, string(videoIDs), time.Now().UnixMilli())
+	return err
+}
+
+func (s *SQLite) DeleteReply(ctx context.Context, parentID snowflake.ID) (replyID snowflake.ID, ok bool, err error) {
+	err = s.db.QueryRowContext(ctx, sqliteDeleteReplyQuery, parentID).Scan(&replyID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil
+		}
+		return
+	}
+	ok = true
+	return
+}
+
+func (s *SQLite) DeleteExpiredReplies(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, sqliteDeleteExpiredRepliesQuery, before.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (s *SQLite) transaction(ctx context.Context, f func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := f(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func collectRows[T any](rows *sql.Rows, scan func(*sql.Rows) (T, error)) ([]T, error) {
+	defer rows.Close()
+	var values []T
+	for rows.Next() {
+		value, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// marshalIDs encodes the IDs as a JSON array of numbers, as snowflake.ID is encoded as a string which SQLite wouldn't
+// compare to integer columns.
+func marshalIDs(ids []snowflake.ID) (string, error) {
+	numbers := make([]uint64, len(ids))
+	for i, id := range ids {
+		numbers[i] = uint64(id)
+	}
+	b, err := json.Marshal(numbers)
+	return string(b), err
+}
+
+func unmarshalIDs(raw string) ([]snowflake.ID, error) {
+	var numbers []uint64
+	if err := json.Unmarshal([]byte(raw), &numbers); err != nil {
+		return nil, err
+	}
+	if len(numbers) == 0 {
+		return nil, nil
+	}
+	ids := make([]snowflake.ID, len(numbers))
+	for i, number := range numbers {
+		ids[i] = snowflake.ID(number)
+	}
+	return ids, nil
+}
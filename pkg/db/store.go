@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"dearrow-bot/pkg/config"
+	"fmt"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Drivers which can be passed to Open.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+// GuildStore persists the configs of guilds.
+type GuildStore interface {
+	// GetGuildConfig returns the config of the guild, which is the zero value for guilds without one.
+	GetGuildConfig(ctx context.Context, guildID snowflake.ID) (config.Guild, error)
+	UpdateGuildThumbnailMode(ctx context.Context, guildID snowflake.ID, mode config.ThumbnailMode) error
+	UpdateGuildTitleMode(ctx context.Context, guildID snowflake.ID, mode config.OriginalTitleMode) error
+	UpdateGuildLinkMode(ctx context.Context, guildID snowflake.ID, mode config.LinkMode) error
+	UpdateGuildTitleFormat(ctx context.Context, guildID snowflake.ID, mode config.TitleFormat) error
+	UpdateGuildLocale(ctx context.Context, guildID snowflake.ID, locale string) error
+	UpdateGuildDisabled(ctx context.Context, guildID snowflake.ID, disabled bool) error
+	UpdateGuildCasualMode(ctx context.Context, guildID snowflake.ID, enabled bool) error
+	// UpdateGuildCasualThreshold sets the votes needed in the category, keeping the other categories.
+	UpdateGuildCasualThreshold(ctx context.Context, guildID snowflake.ID, category config.CasualCategory, votes int) error
+	UpdateGuildPolicy(ctx context.Context, guildID snowflake.ID, target config.PolicyTarget, policy config.Policy) error
+	// AddToGuildList appends the ID to the list, moving it to the end if it's already contained.
+	AddToGuildList(ctx context.Context, guildID snowflake.ID, list config.List, id snowflake.ID) error
+	RemoveFromGuildList(ctx context.Context, guildID snowflake.ID, list config.List, id snowflake.ID) error
+}
+
+// ChannelStore persists the overrides of channels.
+type ChannelStore interface {
+	// GetChannelConfigs returns the overrides of the provided channels. Channels without overrides are omitted.
+	GetChannelConfigs(ctx context.Context, guildID snowflake.ID, channelIDs []snowflake.ID) ([]config.Channel, error)
+	// UpdateChannelConfig sets the non-nil overrides of the channel, keeping the existing ones.
+	UpdateChannelConfig(ctx context.Context, guildID snowflake.ID, cfg config.Channel) error
+	// DeleteChannelConfig removes all overrides of the channel and returns whether there were any.
+	DeleteChannelConfig(ctx context.Context, guildID snowflake.ID, channelID snowflake.ID) (bool, error)
+}
+
+// UserStore persists the preferences of users.
+type UserStore interface {
+	// GetUserConfig returns the preferences of the user, which is the zero value for users without any.
+	GetUserConfig(ctx context.Context, userID snowflake.ID) (config.User, error)
+	UpdateUserOptedOut(ctx context.Context, userID snowflake.ID, optedOut bool) error
+}
+
+// Store persists everything the bot needs to remember.
+type Store interface {
+	GuildStore
+	ChannelStore
+	UserStore
+	ReplyStore
+	// Migrate brings the schema up to date.
+	Migrate(ctx context.Context) error
+	// Close releases the underlying connections.
+	Close() error
+}
+
+var (
+	_ Store = (*Postgres)(nil)
+	_ Store = (*SQLite)(nil)
+	_ Store = (*Memory)(nil)
+)
+
+// Open connects to the store of the driver, which defaults to DriverPostgres. The dsn is the connection string for
+// Postgres and the path of the database file for SQLite.
+func Open(ctx context.Context, driver string, dsn string) (Store, error) {
+	switch driver {
+	case "", DriverPostgres:
+		pool, err := pgxpool.New(ctx, dsn)
+		if err != nil {
+			return nil, err
+		}
+		return NewPostgres(pool), nil
+	case DriverSQLite:
+		return OpenSQLite(dsn)
+	case DriverMemory:
+		return NewMemory(), nil
+	}
+	return nil, fmt.Errorf("unknown storage driver: %s", driver)
+}
@@ -0,0 +1,231 @@
+package db
+
+import (
+	"dearrow-bot/pkg/config"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	guildID      snowflake.ID = 1
+	otherGuildID snowflake.ID = 2
+)
+
+// stores returns the stores which can be tested without external services.
+func stores(t *testing.T) map[string]Store {
+	t.Helper()
+	sqlite, err := OpenSQLite(filepath.Join(t.TempDir(), "dearrow.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = sqlite.Close()
+	})
+	stores := map[string]Store{
+		DriverMemory: NewMemory(),
+		DriverSQLite: sqlite,
+	}
+	for _, store := range stores {
+		for range 2 { // migrating twice must not reapply anything
+			if err := store.Migrate(t.Context()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return stores
+}
+
+func TestStoreGuildConfig(t *testing.T) {
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			cfg, err := store.GetGuildConfig(ctx, guildID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(cfg, config.Guild{}) {
+				t.Errorf("expected the zero config, got %+v", cfg)
+			}
+
+			updates := []error{
+				store.UpdateGuildThumbnailMode(ctx, guildID, config.ThumbnailModeBlank),
+				store.UpdateGuildTitleMode(ctx, guildID, config.OriginalTitleModeHidden),
+				store.UpdateGuildLinkMode(ctx, guildID, config.LinkModeContent),
+				store.UpdateGuildTitleFormat(ctx, guildID, config.TitleFormatSentenceCase),
+				store.UpdateGuildLocale(ctx, guildID, "de"),
+				store.UpdateGuildDisabled(ctx, guildID, true),
+				store.UpdateGuildCasualMode(ctx, guildID, true),
+				store.UpdateGuildCasualThreshold(ctx, guildID, config.CasualCategoryFunny, 3),
+				store.UpdateGuildCasualThreshold(ctx, guildID, config.CasualCategoryOther, 0),
+				store.UpdateGuildPolicy(ctx, guildID, config.PolicyTargetTitles, config.Policy{MinVotes: 2, LockedOnly: true}),
+				store.UpdateGuildPolicy(ctx, guildID, config.PolicyTargetThumbnails, config.Policy{RequireNonOriginal: true}),
+				store.AddToGuildList(ctx, guildID, config.ListChannelAllow, 10),
+				store.AddToGuildList(ctx, guildID, config.ListChannelAllow, 11),
+				store.AddToGuildList(ctx, guildID, config.ListChannelAllow, 10),
+				store.AddToGuildList(ctx, guildID, config.ListRoleDeny, 20),
+				store.RemoveFromGuildList(ctx, guildID, config.ListRoleDeny, 20),
+				store.RemoveFromGuildList(ctx, guildID, config.ListChannelDeny, 30),
+			}
+			for i, err := range updates {
+				if err != nil {
+					t.Fatalf("update %d: %v", i, err)
+				}
+			}
+			cfg, err = store.GetGuildConfig(ctx, guildID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := config.Guild{
+				ThumbnailMode:               config.ThumbnailModeBlank,
+				OriginalTitleMode:           config.OriginalTitleModeHidden,
+				LinkMode:                    config.LinkModeContent,
+				TitleFormat:                 config.TitleFormatSentenceCase,
+				Locale:                      "de",
+				Disabled:                    true,
+				ChannelAllowlist:            []snowflake.ID{11, 10},
+				CasualMode:                  true,
+				CasualThresholds:            map[string]int{"funny": 3, "other": 0},
+				TitleMinVotes:               2,
+				TitleLockedOnly:             true,
+				ThumbnailRequireNonOriginal: true,
+			}
+			if len(cfg.RoleDenylist) != 0 {
+				t.Errorf("expected an empty role denylist, got %v", cfg.RoleDenylist)
+			}
+			cfg.RoleDenylist = nil
+			if !reflect.DeepEqual(cfg, expected) {
+				t.Errorf("expected %+v, got %+v", expected, cfg)
+			}
+
+			if other, err := store.GetGuildConfig(ctx, otherGuildID); err != nil || !reflect.DeepEqual(other, config.Guild{}) {
+				t.Errorf("expected other guilds to be unaffected, got %+v (%v)", other, err)
+			}
+		})
+	}
+}
+
+func TestStoreChannelConfig(t *testing.T) {
+	const (
+		channelID snowflake.ID = 10
+		threadID  snowflake.ID = 11
+	)
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := store.UpdateChannelConfig(ctx, guildID, config.Channel{ChannelID: channelID, Enabled: new(false), Locale: new("de")}); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.UpdateChannelConfig(ctx, guildID, config.Channel{ChannelID: channelID, ThumbnailMode: new(config.ThumbnailModeOriginal)}); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.UpdateChannelConfig(ctx, guildID, config.Channel{ChannelID: threadID, OriginalTitleMode: new(config.OriginalTitleModeHidden)}); err != nil {
+				t.Fatal(err)
+			}
+
+			overrides, err := store.GetChannelConfigs(ctx, guildID, []snowflake.ID{channelID, 12})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := []config.Channel{{ChannelID: channelID, Enabled: new(false), ThumbnailMode: new(config.ThumbnailModeOriginal), Locale: new("de")}}
+			if !reflect.DeepEqual(overrides, expected) {
+				t.Errorf("expected %+v, got %+v", expected, overrides)
+			}
+			if overrides, err := store.GetChannelConfigs(ctx, otherGuildID, []snowflake.ID{channelID}); err != nil || len(overrides) != 0 {
+				t.Errorf("expected no overrides in another guild, got %+v (%v)", overrides, err)
+			}
+
+			if ok, err := store.DeleteChannelConfig(ctx, guildID, channelID); err != nil || !ok {
+				t.Errorf("expected the overrides to be deleted, got %t (%v)", ok, err)
+			}
+			if ok, err := store.DeleteChannelConfig(ctx, guildID, channelID); err != nil || ok {
+				t.Errorf("expected no overrides to be deleted, got %t (%v)", ok, err)
+			}
+			overrides, err = store.GetChannelConfigs(ctx, guildID, []snowflake.ID{channelID, threadID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(overrides) != 1 || overrides[0].ChannelID != threadID {
+				t.Errorf("expected only the thread overrides, got %+v", overrides)
+			}
+		})
+	}
+}
+
+func TestStoreUserConfig(t *testing.T) {
+	const userID snowflake.ID = 10
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			for _, optedOut := range []bool{false, true, false} {
+				if err := store.UpdateUserOptedOut(ctx, userID, optedOut); err != nil {
+					t.Fatal(err)
+				}
+				cfg, err := store.GetUserConfig(ctx, userID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if cfg.OptedOut != optedOut {
+					t.Errorf("expected opted out to be %t, got %t", optedOut, cfg.OptedOut)
+				}
+			}
+		})
+	}
+}
+
+func TestStoreReplies(t *testing.T) {
+	const (
+		parentID  snowflake.ID = 10
+		channelID snowflake.ID = 11
+	)
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if _, ok, err := store.GetReply(ctx, parentID); err != nil || ok {
+				t.Fatalf("expected no reply, got %t (%v)", ok, err)
+			}
+			for _, reply := range []Reply{{ID: 20, VideoIDs: []string{"a"}}, {ID: 21, VideoIDs: []string{"a", "b"}}} {
+				if err := store.SaveReply(ctx, parentID, channelID, reply); err != nil {
+					t.Fatal(err)
+				}
+				saved, ok, err := store.GetReply(ctx, parentID)
+				if err != nil || !ok {
+					t.Fatalf("expected a reply, got %t (%v)", ok, err)
+				}
+				if saved.ID != reply.ID || !slices.Equal(saved.VideoIDs, reply.VideoIDs) {
+					t.Errorf("expected %+v, got %+v", reply, saved)
+				}
+			}
+
+			if count, err := store.DeleteExpiredReplies(ctx, time.Now().Add(-time.Hour)); err != nil || count != 0 {
+				t.Errorf("expected no expired replies, got %d (%v)", count, err)
+			}
+			replyID, ok, err := store.DeleteReply(ctx, parentID)
+			if err != nil || !ok || replyID != 21 {
+				t.Errorf("expected reply 21 to be deleted, got %d, %t (%v)", replyID, ok, err)
+			}
+			if _, ok, err := store.DeleteReply(ctx, parentID); err != nil || ok {
+				t.Errorf("expected no reply to be deleted, got %t (%v)", ok, err)
+			}
+
+			if err := store.SaveReply(ctx, parentID, channelID, Reply{ID: 22}); err != nil {
+				t.Fatal(err)
+			}
+			saved := time.Now()
+			time.Sleep(2 * time.Millisecond) // SQLite stores milliseconds
+			if err := store.SaveReply(ctx, parentID, channelID, Reply{ID: 23}); err != nil {
+				t.Fatal(err)
+			}
+			if count, err := store.DeleteExpiredReplies(ctx, saved); err != nil || count != 0 {
+				t.Errorf("expected the updated reply not to be expired, got %d (%v)", count, err)
+			}
+			if count, err := store.DeleteExpiredReplies(ctx, time.Now().Add(time.Hour)); err != nil || count != 1 {
+				t.Errorf("expected one expired reply, got %d (%v)", count, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package db
+
+import (
+	"context"
+	"dearrow-bot/pkg/config"
+	"errors"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	selectUserQuery         = "SELECT opted_out FROM user_preferences WHERE user_id = $1;"
+	upsertUserOptedOutQuery = "INSERT INTO user_preferences (user_id, opted_out) VALUES ($1, $2) ON CONFLICT(user_id) DO UPDATE SET opted_out=excluded.opted_out;"
+)
+
+func (db *Postgres) GetUserConfig(ctx context.Context, userID snowflake.ID) (cfg config.User, err error) {
+	rows, _ := db.pool.Query(ctx, selectUserQuery, userID)
+	cfg, err = pgx.CollectOneRow(rows, pgx.RowToStructByName[config.User])
+	if err != nil && errors.Is(err, pgx.ErrNoRows) {
+		err = nil
+	}
+	return
+}
+
+func (db *Postgres) UpdateUserOptedOut(ctx context.Context, userID snowflake.ID, optedOut bool) error {
+	_, err := db.pool.Exec(ctx, upsertUserOptedOutQuery, userID, optedOut)
+	return err
+}
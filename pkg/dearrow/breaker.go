@@ -0,0 +1,131 @@
+package dearrow
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+type BreakerState int
+
+const (
+	BreakerStateClosed BreakerState = iota
+	BreakerStateOpen
+	BreakerStateHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerStateClosed:
+		return "closed"
+	case BreakerStateOpen:
+		return "open"
+	case BreakerStateHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// circuitBreaker opens after threshold consecutive failures and rejects requests until the cooldown passes. After
+// that a single trial request is let through which either closes the breaker again or reopens it.
+// It is safe for concurrent use.
+type circuitBreaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	trial    bool // whether the trial request of the half-open state is in flight
+}
+
+func newCircuitBreaker(name string, threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+func (b *circuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *circuitBreaker) allow() bool {
+	if b.threshold <= 0 { // disabled
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerStateOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.setState(BreakerStateHalfOpen)
+		b.trial = true
+		return true
+	case BreakerStateHalfOpen:
+		if b.trial {
+			return false
+		}
+		b.trial = true
+		return true
+	}
+	return true
+}
+
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.trial = false
+	if b.state != BreakerStateClosed {
+		b.setState(BreakerStateClosed)
+	}
+}
+
+func (b *circuitBreaker) failure() {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.trial = false
+	if b.state == BreakerStateHalfOpen || (b.state == BreakerStateClosed && b.failures >= b.threshold) {
+		b.openedAt = time.Now()
+		b.setState(BreakerStateOpen)
+	}
+}
+
+// cancel gives up an allowed request without recording its outcome, e.g. because the caller has gone away. A
+// cancelled trial request lets the next one through.
+func (b *circuitBreaker) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
+
+func (b *circuitBreaker) setState(state BreakerState) {
+	level := slog.LevelInfo
+	if state == BreakerStateOpen {
+		level = slog.LevelWarn
+	}
+	slog.Log(context.Background(), level, "dearrow: circuit breaker state changed",
+		slog.String("breaker", b.name),
+		slog.String("breaker.from", b.state.String()),
+		slog.String("breaker.to", state.String()),
+		slog.Int("breaker.failures", b.failures))
+	b.state = state
+}
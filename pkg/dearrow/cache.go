@@ -0,0 +1,106 @@
+package dearrow
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheStats holds the hit and miss counters of a cache.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// lruCache is a size-bounded LRU cache with per-entry expiry. The size of an entry is determined by the cost function.
+// It is safe for concurrent use.
+type lruCache[K comparable, V any] struct {
+	capacity int
+	cost     func(V) int
+	onEvict  func(K, V)
+
+	mu      sync.Mutex
+	size    int
+	entries map[K]*list.Element
+	order   *list.List // front is the most recently used entry
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+type cacheEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	cost      int
+	expiresAt time.Time // zero if the entry doesn't expire
+}
+
+func newLRUCache[K comparable, V any](capacity int, cost func(V) int) *lruCache[K, V] {
+	return &lruCache[K, V]{
+		capacity: capacity,
+		cost:     cost,
+		entries:  make(map[K]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *lruCache[K, V]) get(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		c.misses.Add(1)
+		return
+	}
+	entry := element.Value.(*cacheEntry[K, V])
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.remove(element, true)
+		c.misses.Add(1)
+		return value, false
+	}
+	c.order.MoveToFront(element)
+	c.hits.Add(1)
+	return entry.value, true
+}
+
+// set stores the value for the provided duration. A zero ttl stores the value until it gets evicted.
+func (c *lruCache[K, V]) set(key K, value V, ttl time.Duration) {
+	entry := &cacheEntry[K, V]{
+		key:   key,
+		value: value,
+		cost:  c.cost(value),
+	}
+	if entry.cost > c.capacity { // would evict everything else and still not fit
+		return
+	}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok { // replaced entries aren't reported as evicted
+		c.remove(element, false)
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	c.size += entry.cost
+	for c.size > c.capacity {
+		c.remove(c.order.Back(), true)
+	}
+}
+
+func (c *lruCache[K, V]) remove(element *list.Element, evict bool) {
+	entry := c.order.Remove(element).(*cacheEntry[K, V])
+	delete(c.entries, entry.key)
+	c.size -= entry.cost
+	if evict && c.onEvict != nil {
+		c.onEvict(entry.key, entry.value)
+	}
+}
+
+func (c *lruCache[K, V]) stats() CacheStats {
+	return CacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+}
@@ -1,13 +1,18 @@
 package dearrow
 
 import (
+	"context"
 	"dearrow-bot/pkg/config"
+	"dearrow-bot/pkg/i18n"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/json"
@@ -20,52 +25,174 @@ var (
 )
 
 const (
-	dearrowApiURL   = "https://sponsor.ajay.app/api/branding?videoID=%s&returnUserID=%t"
-	thumbnailApiURL = "https://dearrow-thumb.ajay.app/api/v1/getThumbnail?videoID=%s&time=%.5f&generateNow=true"
+	brandingPath        = "/api/branding?videoID=%s&returnUserID=%t"
+	brandingLocaleParam = "&locale=%s"
+	thumbnailPath       = "/api/v1/getThumbnail?videoID=%s&time=%.5f&generateNow=true"
+
+	casualDownvote = "downvote"
 )
 
 type Client struct {
 	brandingClient  *http.Client
 	thumbnailClient *http.Client
+
+	brandingAPIURL  string
+	thumbnailAPIURL string
+
+	brandingCacheSize        int
+	brandingCacheTTL         time.Duration
+	brandingCacheNotFoundTTL time.Duration
+	brandingCache            *lruCache[string, *BrandingResponse]
+
+	thumbnailCacheSize int
+	thumbnailCacheDir  string
+	thumbnailCache     *thumbnailCache
+
+	retryPolicy      RetryPolicy
+	breakerThreshold int
+	breakerCooldown  time.Duration
+	brandingBreaker  *circuitBreaker
+	thumbnailBreaker *circuitBreaker
 }
 
-func New(brandingClient *http.Client, thumbnailClient *http.Client) *Client {
-	return &Client{
-		brandingClient:  brandingClient,
-		thumbnailClient: thumbnailClient,
+func New(brandingClient *http.Client, thumbnailClient *http.Client, opts ...Option) (*Client, error) {
+	c := &Client{
+		brandingClient:           brandingClient,
+		thumbnailClient:          thumbnailClient,
+		brandingAPIURL:           DefaultBrandingAPIURL,
+		thumbnailAPIURL:          DefaultThumbnailAPIURL,
+		brandingCacheSize:        DefaultBrandingCacheSize,
+		brandingCacheTTL:         DefaultBrandingCacheTTL,
+		brandingCacheNotFoundTTL: DefaultBrandingCacheNotFoundTTL,
+		thumbnailCacheSize:       DefaultThumbnailCacheSize,
+		retryPolicy:              DefaultRetryPolicy,
+		breakerThreshold:         DefaultBreakerThreshold,
+		breakerCooldown:          DefaultBreakerCooldown,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	for _, apiURL := range []*string{&c.brandingAPIURL, &c.thumbnailAPIURL} {
+		u, err := url.Parse(*apiURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid api url: %q", *apiURL)
+		}
+		*apiURL = strings.TrimSuffix(*apiURL, "/")
 	}
+	c.brandingBreaker = newCircuitBreaker("branding", c.breakerThreshold, c.breakerCooldown)
+	c.thumbnailBreaker = newCircuitBreaker("thumbnail", c.breakerThreshold, c.breakerCooldown)
+	if c.brandingCacheSize > 0 {
+		c.brandingCache = newLRUCache[string, *BrandingResponse](c.brandingCacheSize, func(*BrandingResponse) int {
+			return 1
+		})
+	}
+	if c.thumbnailCacheSize > 0 {
+		thumbnailCache, err := newThumbnailCache(c.thumbnailCacheSize, c.thumbnailCacheDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create thumbnail cache: %w", err)
+		}
+		c.thumbnailCache = thumbnailCache
+	}
+	return c, nil
 }
 
-func (c *Client) FetchBranding(videoID string) *BrandingResponse {
-	rs, err := c.FetchBrandingRaw(videoID, false)
+// BrandingCacheStats returns the hit and miss counters of the branding cache.
+func (c *Client) BrandingCacheStats() CacheStats {
+	if c.brandingCache == nil {
+		return CacheStats{}
+	}
+	return c.brandingCache.stats()
+}
+
+// ThumbnailCacheStats returns the hit and miss counters of the thumbnail cache.
+func (c *Client) ThumbnailCacheStats() CacheStats {
+	if c.thumbnailCache == nil {
+		return CacheStats{}
+	}
+	return c.thumbnailCache.entries.stats()
+}
+
+// FetchBranding returns the branding of the video in the locale, or nil if the request fails. If there are no titles
+// in the locale, the default branding is returned instead. An empty locale always returns the default branding.
+// Responses are cached and must not be modified.
+func (c *Client) FetchBranding(ctx context.Context, videoID string, locale string) *BrandingResponse {
+	brandingResponse := c.fetchBranding(ctx, videoID, locale)
+	if locale != "" && brandingResponse != nil && len(brandingResponse.Titles) == 0 {
+		return c.fetchBranding(ctx, videoID, "")
+	}
+	return brandingResponse
+}
+
+func (c *Client) fetchBranding(ctx context.Context, videoID string, locale string) *BrandingResponse {
+	key := videoID
+	if locale != "" {
+		key += "_" + locale
+	}
+	if c.brandingCache != nil {
+		if brandingResponse, ok := c.brandingCache.get(key); ok {
+			return brandingResponse
+		}
+	}
+	rs, err := c.FetchBrandingRaw(ctx, videoID, locale, false)
 	if err != nil {
-		slog.Error("dearrow: error while running a branding request", slog.String("video.id", videoID), tint.Err(err))
+		slog.Error("dearrow: error while running a branding request",
+			slog.String("video.id", videoID),
+			slog.String("locale", locale),
+			slog.String("breaker.state", c.brandingBreaker.State().String()),
+			tint.Err(err))
 		return nil
 	}
+	defer rs.Body.Close()
 	status := rs.StatusCode
 	if status != http.StatusOK && status != http.StatusNotFound {
 		slog.Warn("dearrow: received an unexpected code from a branding response", slog.Int("status.code", status), slog.String("video.id", videoID))
 		return nil
 	}
-	defer rs.Body.Close()
 	var brandingResponse *BrandingResponse
 	if err := json.NewDecoder(rs.Body).Decode(&brandingResponse); err != nil {
 		slog.Error("dearrow: error while decoding a branding response", slog.Int("status.code", status), slog.String("video.id", videoID), tint.Err(err))
 		return nil
 	}
+	if c.brandingCache != nil && brandingResponse != nil {
+		ttl := c.brandingCacheTTL
+		if status == http.StatusNotFound {
+			ttl = c.brandingCacheNotFoundTTL
+		}
+		if ttl > 0 {
+			c.brandingCache.set(key, brandingResponse, ttl)
+		}
+	}
 	return brandingResponse
 }
 
-func (c *Client) FetchBrandingRaw(videoID string, returnUserID bool) (*http.Response, error) {
-	return c.brandingClient.Get(fmt.Sprintf(dearrowApiURL, videoID, returnUserID))
+func (c *Client) FetchBrandingRaw(ctx context.Context, videoID string, locale string, returnUserID bool) (*http.Response, error) {
+	brandingURL := c.brandingAPIURL + fmt.Sprintf(brandingPath, videoID, returnUserID)
+	if locale != "" {
+		brandingURL += fmt.Sprintf(brandingLocaleParam, url.QueryEscape(locale))
+	}
+	return c.get(ctx, c.brandingClient, c.brandingBreaker, brandingURL)
 }
 
-func (c *Client) FetchThumbnail(videoID string, timestamp float64) (io.ReadCloser, error) {
-	thumbnailURL := fmt.Sprintf(thumbnailApiURL, videoID, timestamp)
+// FetchThumbnail returns the thumbnail of the video at the timestamp. Each call returns its own reader, even if
+// the thumbnail is served from the cache.
+func (c *Client) FetchThumbnail(ctx context.Context, videoID string, timestamp float64) (io.ReadCloser, error) {
+	if c.thumbnailCache == nil {
+		return c.downloadThumbnail(ctx, videoID, timestamp)
+	}
+	return c.thumbnailCache.fetch(ctx, videoID, timestamp, func(ctx context.Context) (io.ReadCloser, error) {
+		return c.downloadThumbnail(ctx, videoID, timestamp)
+	})
+}
 
-	rs, err := c.thumbnailClient.Get(thumbnailURL)
+func (c *Client) downloadThumbnail(ctx context.Context, videoID string, timestamp float64) (io.ReadCloser, error) {
+	thumbnailURL := c.thumbnailAPIURL + fmt.Sprintf(thumbnailPath, videoID, timestamp)
+
+	rs, err := c.get(ctx, c.thumbnailClient, c.thumbnailBreaker, thumbnailURL)
 	if err != nil {
-		slog.Error("dearrow: error while downloading a thumbnail", slog.String("thumbnail.url", thumbnailURL), tint.Err(err))
+		slog.Error("dearrow: error while downloading a thumbnail",
+			slog.String("thumbnail.url", thumbnailURL),
+			slog.String("breaker.state", c.thumbnailBreaker.State().String()),
+			tint.Err(err))
 		return nil, err
 	}
 	if rs.StatusCode != http.StatusOK {
@@ -74,6 +201,7 @@ func (c *Client) FetchThumbnail(videoID string, timestamp float64) (io.ReadClose
 			slog.String("failure.reason", rs.Header.Get("X-Failure-Reason")),
 			slog.String("video.id", videoID),
 			slog.String("thumbnail.url", thumbnailURL))
+		rs.Body.Close()
 		return nil, generateErr
 	}
 	return rs.Body, nil
@@ -89,33 +217,55 @@ type BrandingResponse struct {
 	} `json:"titles"`
 	Thumbnails []struct {
 		Timestamp *float64 `json:"timestamp"`
+		Votes     int      `json:"votes"`
 		Original  bool     `json:"original"`
 		Locked    bool     `json:"locked"`
 	} `json:"thumbnails"`
+	CasualVotes []struct {
+		ID    string `json:"id"`
+		Count int    `json:"count"`
+		Title string `json:"title"` // original title the votes were cast for, empty if it's unknown
+	} `json:"casualVotes"`
 	RandomTime float64 `json:"randomTime"`
 }
 
-func (b *BrandingResponse) ToReplacementData(videoID string, cfg config.Guild, embed discord.Embed, debugLogger *slog.Logger) *ReplacementData {
+// ToReplacementData returns the embed replacing the original embed of the video, or nil if there's nothing to replace.
+// Texts added to the embed are translated to the locale.
+func (b *BrandingResponse) ToReplacementData(videoID string, cfg config.Guild, locale discord.Locale, embed discord.Embed, debugLogger *slog.Logger) *ReplacementData {
 	embedBuilder := discord.NewEmbedBuilder()
-	embedBuilder.SetAuthor(embed.Author.Name, embed.Author.URL, "")
+	if embed.Author != nil {
+		embedBuilder.SetAuthor(embed.Author.Name, embed.Author.URL, "")
+	}
 	embedBuilder.SetTitle(embed.Title)
 	embedBuilder.SetURL(embed.URL)
-	embedBuilder.SetFooterText(`Tip: Use Apps -> "Delete embeds" to delete the DeArrow message.`)
+	embedBuilder.SetFooterText(i18n.T(locale, i18n.EmbedFooterTip))
 	embedBuilder.SetColor(embed.Color)
-	embedBuilder.SetImage(embed.Thumbnail.URL)
+	if embed.Thumbnail != nil {
+		embedBuilder.SetImage(embed.Thumbnail.URL)
+	}
 
 	original := embed.Title
-	title := b.replacementTitle(original)
-	timestamp := b.replacementTimestamp(cfg.ThumbnailMode, embedBuilder)
+	if cfg.CasualMode && b.isCasual(cfg, original) {
+		debugLogger.Debug("dearrow: video is casual, keeping the original", slog.String("video.id", videoID))
+		return nil
+	}
+	title := b.replacementTitle(original, cfg.Policy(config.PolicyTargetTitles))
+	replaced := title != ""
+	if replaced {
+		title = FormatTitle(arrowRegex.ReplaceAllString(title, "$1$2"), cfg.TitleFormat)
+	} else if formatted := FormatTitle(original, cfg.TitleFormat); formatted != original { // format the original like the extension
+		title = formatted
+	}
+	timestamp := b.replacementTimestamp(cfg.ThumbnailMode, cfg.Policy(config.PolicyTargetThumbnails), embedBuilder)
 	if title == "" && timestamp == -1 { // nothing to replace
 		debugLogger.Debug("dearrow: nothing to replace for video", slog.String("video.id", videoID))
 		return nil
 	}
 	if title != "" {
-		if cfg.OriginalTitleMode == config.OriginalTitleModeShown {
-			embedBuilder.SetDescription("-# Original title: " + original)
+		if replaced && cfg.OriginalTitleMode == config.OriginalTitleModeShown {
+			embedBuilder.SetDescription("-# " + i18n.T(locale, i18n.EmbedOriginalTitle, original))
 		}
-		embedBuilder.SetTitle(arrowRegex.ReplaceAllString(title, "$1$2"))
+		embedBuilder.SetTitle(title)
 	}
 	if timestamp != -1 {
 		embedBuilder.SetImage("attachment://thumbnail-" + videoID + ".webp")
@@ -123,13 +273,38 @@ func (b *BrandingResponse) ToReplacementData(videoID string, cfg config.Guild, e
 	return &ReplacementData{
 		Timestamp: timestamp,
 		Embed:     embedBuilder.Build(),
+		title:     title,
+		original:  original,
+		locale:    locale,
+	}
+}
+
+// isCasual checks whether enough casual voters consider the original title and thumbnail good enough in any of the
+// categories. Like in the extension, downvotes are subtracted from the votes of every category, and votes cast for a
+// different original title are ignored.
+func (b *BrandingResponse) isCasual(cfg config.Guild, original string) bool {
+	downvotes := 0
+	for _, vote := range b.CasualVotes {
+		if vote.ID == casualDownvote {
+			downvotes = vote.Count
+		}
 	}
+	for _, vote := range b.CasualVotes {
+		if vote.ID == casualDownvote || (vote.Title != "" && vote.Title != original) {
+			continue
+		}
+		threshold := cfg.CasualThreshold(config.CasualCategory(vote.ID))
+		if threshold > 0 && vote.Count-downvotes >= threshold {
+			return true
+		}
+	}
+	return false
 }
 
-func (b *BrandingResponse) replacementTitle(original string) string {
-	if len(b.Titles) != 0 && b.Titles[0].Votes > -1 {
+func (b *BrandingResponse) replacementTitle(original string, policy config.Policy) string {
+	if len(b.Titles) != 0 {
 		title := b.Titles[0]
-		if (title.Original && !title.Locked) || title.Title == original {
+		if !accepts(policy, title.Votes, title.Original, title.Locked) || title.Title == original {
 			return ""
 		}
 		return title.Title
@@ -137,10 +312,10 @@ func (b *BrandingResponse) replacementTitle(original string) string {
 	return ""
 }
 
-func (b *BrandingResponse) replacementTimestamp(mode config.ThumbnailMode, embedBuilder *discord.EmbedBuilder) float64 {
+func (b *BrandingResponse) replacementTimestamp(mode config.ThumbnailMode, policy config.Policy, embedBuilder *discord.EmbedBuilder) float64 {
 	if len(b.Thumbnails) != 0 {
 		thumbnail := b.Thumbnails[0]
-		if (thumbnail.Original && !thumbnail.Locked) || thumbnail.Timestamp == nil {
+		if !accepts(policy, thumbnail.Votes, thumbnail.Original, thumbnail.Locked) || thumbnail.Timestamp == nil {
 			return -1
 		}
 		return *thumbnail.Timestamp
@@ -158,11 +333,47 @@ func (b *BrandingResponse) replacementTimestamp(mode config.ThumbnailMode, embed
 	return -1
 }
 
+// accepts checks whether a submission may replace the original under the policy. Submissions voting for the
+// original are only used if they're locked, as the original may have changed since.
+func accepts(policy config.Policy, votes int, original bool, locked bool) bool {
+	if votes < policy.MinVotes || (policy.LockedOnly && !locked) {
+		return false
+	}
+	return !original || (locked && !policy.RequireNonOriginal)
+}
+
 type ReplacementData struct {
 	Embed     discord.Embed
 	Timestamp float64
+
+	title    string // empty if the title isn't replaced or formatted
+	original string
+	locale   discord.Locale
 }
 
 func (d *ReplacementData) ToEmbed() discord.Embed {
 	return d.Embed
 }
+
+// ToSpoiler hides the replacement behind spoilers as embeds themselves can't be marked as spoilers. Thumbnails
+// can't be hidden and are dropped, so nil is returned if the title isn't replaced.
+func (d *ReplacementData) ToSpoiler() *ReplacementData {
+	if d.title == "" {
+		return nil
+	}
+	embed := d.Embed
+	embed.Title = i18n.T(d.locale, i18n.EmbedSpoiler)
+	description := "||" + d.title + "||"
+	if embed.Description != "" { // original title is shown
+		description += "\n-# " + i18n.T(d.locale, i18n.EmbedOriginalTitle, "||"+d.original+"||")
+	}
+	embed.Description = description
+	embed.Image = nil
+	return &ReplacementData{
+		Embed:     embed,
+		Timestamp: -1,
+		title:     d.title,
+		original:  d.original,
+		locale:    d.locale,
+	}
+}
@@ -0,0 +1,601 @@
+package dearrow_test
+
+import (
+	"context"
+	"dearrow-bot/pkg/config"
+	"dearrow-bot/pkg/dearrow"
+	"dearrow-bot/pkg/dearrow/dearrowtest"
+	"dearrow-bot/pkg/i18n"
+	"dearrow-bot/pkg/util"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+const (
+	videoID      = "dQw4w9WgXcQ"
+	otherVideoID = "jNQXAC9IVRw"
+)
+
+var (
+	discardLogger = slog.New(slog.DiscardHandler)
+)
+
+func newClient(t *testing.T, server *dearrowtest.Server, opts ...dearrow.Option) *dearrow.Client {
+	t.Helper()
+	opts = append([]dearrow.Option{
+		dearrow.WithAPIURLs(server.URL, server.URL),
+		dearrow.WithRetryPolicy(dearrow.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}),
+		dearrow.WithThumbnailCache(0, ""),
+	}, opts...)
+	client, err := dearrow.New(&http.Client{Timeout: 100 * time.Millisecond}, &http.Client{Timeout: 100 * time.Millisecond}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func newServer(t *testing.T) *dearrowtest.Server {
+	t.Helper()
+	server := dearrowtest.NewServer()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func branding(title string, timestamp float64) dearrowtest.Response {
+	return dearrowtest.JSON(map[string]any{
+		"titles": []map[string]any{
+			{"title": title, "votes": 1, "original": false, "locked": false},
+		},
+		"thumbnails": []map[string]any{
+			{"timestamp": timestamp, "original": false, "locked": false},
+		},
+		"randomTime":    0.5,
+		"videoDuration": 100.0,
+	})
+}
+
+// casualBranding returns a branding response replacing the title and thumbnail with the casual votes per category.
+func casualBranding(votes map[string]int) dearrowtest.Response {
+	casualVotes := make([]map[string]any, 0, len(votes))
+	for id, count := range votes {
+		casualVotes = append(casualVotes, map[string]any{"id": id, "count": count})
+	}
+	return dearrowtest.JSON(map[string]any{
+		"titles": []map[string]any{
+			{"title": "Replaced title", "votes": 1, "original": false, "locked": false},
+		},
+		"thumbnails": []map[string]any{
+			{"timestamp": 12.5, "original": false, "locked": false},
+		},
+		"casualVotes": casualVotes,
+	})
+}
+
+func TestFetchBranding(t *testing.T) {
+	server := newServer(t)
+	server.SetBranding(videoID, branding("Never Gonna Give You Up", 12.5))
+	client := newClient(t, server)
+
+	rs := client.FetchBranding(t.Context(), videoID, "")
+	if rs == nil {
+		t.Fatal("expected a branding response")
+	}
+	if len(rs.Titles) != 1 || rs.Titles[0].Title != "Never Gonna Give You Up" {
+		t.Fatalf("unexpected titles: %+v", rs.Titles)
+	}
+	if len(rs.Thumbnails) != 1 || rs.Thumbnails[0].Timestamp == nil || *rs.Thumbnails[0].Timestamp != 12.5 {
+		t.Fatalf("unexpected thumbnails: %+v", rs.Thumbnails)
+	}
+}
+
+func TestFetchBrandingNotFound(t *testing.T) {
+	server := newServer(t)
+	client := newClient(t, server)
+
+	rs := client.FetchBranding(t.Context(), videoID, "")
+	if rs == nil {
+		t.Fatal("expected a branding response for a video without submissions")
+	}
+	if len(rs.Titles) != 0 || len(rs.Thumbnails) != 0 {
+		t.Fatalf("expected no submissions, got %+v", rs)
+	}
+	if requests := server.BrandingRequests(videoID); requests != 1 {
+		t.Fatalf("expected 404s not to be retried, got %d requests", requests)
+	}
+}
+
+func TestFetchBrandingLocale(t *testing.T) {
+	server := newServer(t)
+	server.SetBranding(videoID, branding("Default title", 1))
+	server.SetLocalizedBranding(videoID, "de", branding("Lokalisierter Titel", 1))
+	client := newClient(t, server)
+
+	rs := client.FetchBranding(t.Context(), videoID, "de")
+	if rs == nil || len(rs.Titles) != 1 || rs.Titles[0].Title != "Lokalisierter Titel" {
+		t.Fatalf("expected the localized title, got %+v", rs)
+	}
+	if requests := server.BrandingRequests(videoID); requests != 1 {
+		t.Fatalf("expected a single request, got %d", requests)
+	}
+}
+
+func TestFetchBrandingLocaleFallback(t *testing.T) {
+	server := newServer(t)
+	server.SetBranding(videoID, branding("Default title", 1))
+	client := newClient(t, server)
+
+	rs := client.FetchBranding(t.Context(), videoID, "fr")
+	if rs == nil || len(rs.Titles) != 1 || rs.Titles[0].Title != "Default title" {
+		t.Fatalf("expected the default title, got %+v", rs)
+	}
+	if requests := server.BrandingRequests(videoID); requests != 2 {
+		t.Fatalf("expected a localized and a default request, got %d", requests)
+	}
+}
+
+func TestFetchBrandingCache(t *testing.T) {
+	server := newServer(t)
+	server.SetBranding(videoID, branding("Title", 1))
+	client := newClient(t, server, dearrow.WithBrandingCache(16, time.Minute, time.Minute))
+
+	for range 3 {
+		if client.FetchBranding(t.Context(), videoID, "") == nil {
+			t.Fatal("expected a branding response")
+		}
+	}
+	if requests := server.BrandingRequests(videoID); requests != 1 {
+		t.Fatalf("expected a single request, got %d", requests)
+	}
+	if stats := client.BrandingCacheStats(); stats.Hits != 2 || stats.Misses != 1 {
+		t.Fatalf("unexpected cache stats: %+v", stats)
+	}
+}
+
+func TestFetchBrandingCacheDisabledTTL(t *testing.T) {
+	server := newServer(t)
+	server.SetBranding(videoID, branding("Title", 1))
+	client := newClient(t, server, dearrow.WithBrandingCache(16, time.Minute, 0))
+
+	for range 2 {
+		if client.FetchBranding(t.Context(), videoID, "") == nil {
+			t.Fatal("expected a branding response")
+		}
+		if client.FetchBranding(t.Context(), otherVideoID, "") == nil {
+			t.Fatal("expected a not found branding response")
+		}
+	}
+	if requests := server.BrandingRequests(videoID); requests != 1 {
+		t.Fatalf("expected a single request, got %d", requests)
+	}
+	if requests := server.BrandingRequests(otherVideoID); requests != 2 {
+		t.Fatalf("expected not found responses not to be cached, got %d requests", requests)
+	}
+}
+
+func TestFetchBrandingServerError(t *testing.T) {
+	server := newServer(t)
+	server.SetBranding(videoID, dearrowtest.Status(http.StatusInternalServerError))
+	client := newClient(t, server)
+
+	if rs := client.FetchBranding(t.Context(), videoID, ""); rs != nil {
+		t.Fatalf("expected no branding response, got %+v", rs)
+	}
+	if requests := server.BrandingRequests(videoID); requests != 3 {
+		t.Fatalf("expected 3 attempts, got %d", requests)
+	}
+}
+
+// bodyTracker counts the response bodies which haven't been closed.
+type bodyTracker struct {
+	open atomic.Int32
+}
+
+func (t *bodyTracker) RoundTrip(rq *http.Request) (*http.Response, error) {
+	rs, err := http.DefaultTransport.RoundTrip(rq)
+	if err != nil {
+		return nil, err
+	}
+	t.open.Add(1)
+	rs.Body = &trackedBody{ReadCloser: rs.Body, tracker: t}
+	return rs, nil
+}
+
+type trackedBody struct {
+	io.ReadCloser
+	tracker *bodyTracker
+	once    sync.Once
+}
+
+func (b *trackedBody) Close() error {
+	b.once.Do(func() {
+		b.tracker.open.Add(-1)
+	})
+	return b.ReadCloser.Close()
+}
+
+func TestFetchBrandingUnexpectedStatusBody(t *testing.T) {
+	server := newServer(t)
+	server.SetBranding(videoID, dearrowtest.Status(http.StatusInternalServerError))
+	server.SetBranding(otherVideoID, dearrowtest.Status(http.StatusForbidden))
+	tracker := &bodyTracker{}
+	httpClient := &http.Client{Timeout: 100 * time.Millisecond, Transport: tracker}
+	client, err := dearrow.New(httpClient, httpClient,
+		dearrow.WithAPIURLs(server.URL, server.URL),
+		dearrow.WithRetryPolicy(dearrow.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+		dearrow.WithThumbnailCache(0, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{videoID, otherVideoID} {
+		if rs := client.FetchBranding(t.Context(), id, ""); rs != nil {
+			t.Fatalf("expected no branding response, got %+v", rs)
+		}
+	}
+	if open := tracker.open.Load(); open != 0 {
+		t.Fatalf("expected all response bodies to be closed, %d are open", open)
+	}
+}
+
+func TestFetchBrandingRetry(t *testing.T) {
+	server := newServer(t)
+	unavailable := dearrowtest.Status(http.StatusServiceUnavailable)
+	unavailable.Header = http.Header{"Retry-After": {"0"}}
+	server.SetBranding(videoID, unavailable, branding("Title", 1))
+	client := newClient(t, server)
+
+	if client.FetchBranding(t.Context(), videoID, "") == nil {
+		t.Fatal("expected the retried request to succeed")
+	}
+	if requests := server.BrandingRequests(videoID); requests != 2 {
+		t.Fatalf("expected 2 attempts, got %d", requests)
+	}
+}
+
+func TestFetchBrandingTimeout(t *testing.T) {
+	server := newServer(t)
+	slow := branding("Title", 1)
+	slow.Delay = 300 * time.Millisecond
+	server.SetBranding(videoID, slow)
+	client := newClient(t, server, dearrow.WithRetryPolicy(dearrow.RetryPolicy{MaxAttempts: 1}))
+
+	if rs := client.FetchBranding(t.Context(), videoID, ""); rs != nil {
+		t.Fatalf("expected no branding response, got %+v", rs)
+	}
+}
+
+func TestFetchBrandingCircuitBreaker(t *testing.T) {
+	server := newServer(t)
+	server.SetBranding(videoID, dearrowtest.Status(http.StatusBadGateway))
+	client := newClient(t, server,
+		dearrow.WithRetryPolicy(dearrow.RetryPolicy{MaxAttempts: 1}),
+		dearrow.WithCircuitBreaker(2, time.Hour))
+
+	for range 2 {
+		client.FetchBranding(t.Context(), videoID, "")
+	}
+	if _, err := client.FetchBrandingRaw(t.Context(), videoID, "", false); !errors.Is(err, dearrow.ErrCircuitOpen) {
+		t.Fatalf("expected an open circuit, got %v", err)
+	}
+	if requests := server.BrandingRequests(videoID); requests != 2 {
+		t.Fatalf("expected requests to stop once the circuit is open, got %d", requests)
+	}
+}
+
+func TestFetchBrandingCancelled(t *testing.T) {
+	server := newServer(t)
+	slow := branding("Title", 1)
+	slow.Delay = 50 * time.Millisecond
+	server.SetBranding(videoID, slow, branding("Title", 1))
+	client := newClient(t, server, dearrow.WithCircuitBreaker(1, time.Hour))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.FetchBrandingRaw(ctx, videoID, "", false); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to be exceeded, got %v", err)
+	}
+	if requests := server.BrandingRequests(videoID); requests != 1 {
+		t.Fatalf("expected cancelled requests not to be retried, got %d requests", requests)
+	}
+	if client.FetchBranding(t.Context(), videoID, "") == nil {
+		t.Fatal("expected cancelled requests not to open the circuit")
+	}
+}
+
+func TestFetchThumbnail(t *testing.T) {
+	server := newServer(t)
+	server.SetThumbnail(videoID, dearrowtest.Response{Status: http.StatusOK, Body: []byte("webp")})
+	client := newClient(t, server)
+
+	body, err := client.FetchThumbnail(t.Context(), videoID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	b, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "webp" {
+		t.Fatalf("unexpected thumbnail: %q", b)
+	}
+}
+
+func TestFetchThumbnailRetryAuthorization(t *testing.T) {
+	t.Setenv("DEARROW_PRIORITY_KEY", "key")
+	server := newServer(t)
+	server.SetThumbnail(videoID, dearrowtest.Status(http.StatusServiceUnavailable), dearrowtest.Response{Status: http.StatusOK, Body: []byte("webp")})
+	client, err := dearrow.New(util.NewBrandingClient(), util.NewThumbnailClient(),
+		dearrow.WithAPIURLs(server.URL, server.URL),
+		dearrow.WithRetryPolicy(dearrow.RetryPolicy{MaxAttempts: 2}),
+		dearrow.WithThumbnailCache(0, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := client.FetchThumbnail(t.Context(), videoID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body.Close()
+	headers := server.ThumbnailHeaders(videoID)
+	if len(headers) != 2 {
+		t.Fatalf("expected 2 attempts, got %d", len(headers))
+	}
+	for i, header := range headers {
+		if values := header.Values("Authorization"); len(values) != 1 || values[0] != "key" {
+			t.Errorf("expected attempt %d to carry the priority key once, got %q", i+1, values)
+		}
+	}
+}
+
+func TestFetchThumbnailFailure(t *testing.T) {
+	server := newServer(t)
+	server.SetThumbnail(videoID, dearrowtest.ThumbnailFailure(http.StatusNoContent, "Failed to generate"))
+	client := newClient(t, server)
+
+	if _, err := client.FetchThumbnail(t.Context(), videoID, 1); err == nil {
+		t.Fatal("expected an error")
+	}
+	if requests := server.ThumbnailRequests(videoID); requests != 1 {
+		t.Fatalf("expected failures not to be retried, got %d requests", requests)
+	}
+}
+
+func TestFetchThumbnailCache(t *testing.T) {
+	server := newServer(t)
+	server.SetThumbnail(videoID, dearrowtest.Response{Status: http.StatusOK, Body: []byte("webp")})
+	client := newClient(t, server, dearrow.WithThumbnailCache(1024, t.TempDir()))
+
+	for range 3 {
+		body, err := client.FetchThumbnail(t.Context(), videoID, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b, _ := io.ReadAll(body); string(b) != "webp" {
+			t.Fatalf("unexpected thumbnail: %q", b)
+		}
+		body.Close()
+	}
+	if requests := server.ThumbnailRequests(videoID); requests != 1 {
+		t.Fatalf("expected a single request, got %d", requests)
+	}
+}
+
+func TestFetchThumbnailCacheCancel(t *testing.T) {
+	server := newServer(t)
+	server.SetThumbnail(videoID, dearrowtest.Response{Status: http.StatusOK, Body: []byte("webp"), Delay: 50 * time.Millisecond})
+	client := newClient(t, server, dearrow.WithThumbnailCache(1024, ""))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancelled := make(chan error, 1)
+	go func() {
+		_, err := client.FetchThumbnail(ctx, videoID, 1)
+		cancelled <- err
+	}()
+	for server.ThumbnailRequests(videoID) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	waiter := make(chan error, 1)
+	go func() {
+		body, err := client.FetchThumbnail(t.Context(), videoID, 1)
+		if err == nil {
+			body.Close()
+		}
+		waiter <- err
+	}()
+	time.Sleep(10 * time.Millisecond) // lets the second call join the download
+	cancel()
+
+	if err := <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancelled call to stop waiting, got %v", err)
+	}
+	if err := <-waiter; err != nil {
+		t.Fatalf("expected the download to continue for the other call, got %v", err)
+	}
+	if requests := server.ThumbnailRequests(videoID); requests != 1 {
+		t.Fatalf("expected a single request, got %d", requests)
+	}
+}
+
+func TestToReplacementData(t *testing.T) {
+	embed := discord.Embed{
+		Title:     "Original",
+		URL:       "https://www.youtube.com/watch?v=" + videoID,
+		Author:    &discord.EmbedAuthor{Name: "Author"},
+		Thumbnail: &discord.EmbedResource{URL: "https://i.ytimg.com/vi/" + videoID + "/maxresdefault.jpg"},
+	}
+	tests := []struct {
+		name        string
+		response    dearrowtest.Response
+		config      config.Guild
+		locale      discord.Locale
+		nilData     bool
+		title       string
+		description string
+		image       string
+		timestamp   float64
+	}{
+		{
+			name:        "title and thumbnail",
+			response:    branding(">Replaced title", 12.5),
+			title:       "Replaced title",
+			description: "-# Original title: Original",
+			image:       "attachment://thumbnail-" + videoID + ".webp",
+			timestamp:   12.5,
+		},
+		{
+			name:        "translated",
+			response:    branding("Replaced title", 12.5),
+			locale:      discord.LocaleGerman,
+			title:       "Replaced title",
+			description: "-# Originaltitel: Original",
+			image:       "attachment://thumbnail-" + videoID + ".webp",
+			timestamp:   12.5,
+		},
+		{
+			name:      "hidden original title",
+			response:  branding("Replaced title", 12.5),
+			config:    config.Guild{OriginalTitleMode: config.OriginalTitleModeHidden},
+			title:     "Replaced title",
+			image:     "attachment://thumbnail-" + videoID + ".webp",
+			timestamp: 12.5,
+		},
+		{
+			name:      "random time",
+			response:  dearrowtest.JSON(map[string]any{"randomTime": 0.5, "videoDuration": 100.0}),
+			title:     "Original",
+			image:     "attachment://thumbnail-" + videoID + ".webp",
+			timestamp: 50,
+		},
+		{
+			name:     "blank thumbnail without submissions",
+			response: dearrowtest.BrandingNotFound(),
+			config:   config.Guild{ThumbnailMode: config.ThumbnailModeBlank},
+			nilData:  true,
+		},
+		{
+			name:     "original thumbnail without submissions",
+			response: dearrowtest.BrandingNotFound(),
+			config:   config.Guild{ThumbnailMode: config.ThumbnailModeOriginal},
+			nilData:  true,
+		},
+		{
+			name:     "same title",
+			response: dearrowtest.JSON(map[string]any{"titles": []map[string]any{{"title": "Original", "votes": 1}}}),
+			config:   config.Guild{ThumbnailMode: config.ThumbnailModeOriginal},
+			nilData:  true,
+		},
+		{
+			name:        "formatted title",
+			response:    branding("replaced title", 12.5),
+			config:      config.Guild{TitleFormat: config.TitleFormatTitleCase},
+			title:       "Replaced Title",
+			description: "-# Original title: Original",
+			image:       "attachment://thumbnail-" + videoID + ".webp",
+			timestamp:   12.5,
+		},
+		{
+			name:      "formatted original title",
+			response:  dearrowtest.BrandingNotFound(),
+			config:    config.Guild{ThumbnailMode: config.ThumbnailModeOriginal, TitleFormat: config.TitleFormatLowerCase},
+			title:     "original",
+			image:     "https://i.ytimg.com/vi/" + videoID + "/maxresdefault.jpg",
+			timestamp: -1,
+		},
+		{
+			name:     "casual",
+			response: casualBranding(map[string]int{"funny": 2}),
+			config:   config.Guild{CasualMode: true},
+			nilData:  true,
+		},
+		{
+			name:        "casual mode disabled",
+			response:    casualBranding(map[string]int{"funny": 2}),
+			title:       "Replaced title",
+			description: "-# Original title: Original",
+			image:       "attachment://thumbnail-" + videoID + ".webp",
+			timestamp:   12.5,
+		},
+		{
+			name:        "casual below threshold",
+			response:    casualBranding(map[string]int{"funny": 2}),
+			config:      config.Guild{CasualMode: true, CasualThresholds: map[string]int{"funny": 3}},
+			title:       "Replaced title",
+			description: "-# Original title: Original",
+			image:       "attachment://thumbnail-" + videoID + ".webp",
+			timestamp:   12.5,
+		},
+		{
+			name:        "casual in ignored category",
+			response:    casualBranding(map[string]int{"clever": 5}),
+			config:      config.Guild{CasualMode: true, CasualThresholds: map[string]int{"clever": 0}},
+			title:       "Replaced title",
+			description: "-# Original title: Original",
+			image:       "attachment://thumbnail-" + videoID + ".webp",
+			timestamp:   12.5,
+		},
+		{
+			name:        "casual with downvotes",
+			response:    casualBranding(map[string]int{"descriptive": 2, "downvote": 2}),
+			config:      config.Guild{CasualMode: true},
+			title:       "Replaced title",
+			description: "-# Original title: Original",
+			image:       "attachment://thumbnail-" + videoID + ".webp",
+			timestamp:   12.5,
+		},
+		{
+			name:     "downvoted title",
+			response: dearrowtest.JSON(map[string]any{"titles": []map[string]any{{"title": "Replaced title", "votes": -1}}}),
+			config:   config.Guild{ThumbnailMode: config.ThumbnailModeOriginal},
+			nilData:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newServer(t)
+			server.SetBranding(videoID, tt.response)
+			client := newClient(t, server, dearrow.WithBrandingCache(0, 0, 0))
+
+			rs := client.FetchBranding(t.Context(), videoID, "")
+			if rs == nil {
+				t.Fatal("expected a branding response")
+			}
+			locale := tt.locale
+			if locale == "" {
+				locale = i18n.DefaultLocale
+			}
+			data := rs.ToReplacementData(videoID, tt.config, locale, embed, discardLogger)
+			if tt.nilData {
+				if data != nil {
+					t.Fatalf("expected nothing to replace, got %+v", data)
+				}
+				return
+			}
+			if data == nil {
+				t.Fatal("expected replacement data")
+			}
+			e := data.ToEmbed()
+			if e.Title != tt.title {
+				t.Errorf("expected title %q, got %q", tt.title, e.Title)
+			}
+			if e.Description != tt.description {
+				t.Errorf("expected description %q, got %q", tt.description, e.Description)
+			}
+			if e.Image == nil || e.Image.URL != tt.image {
+				t.Errorf("expected image %q, got %+v", tt.image, e.Image)
+			}
+			if data.Timestamp != tt.timestamp {
+				t.Errorf("expected timestamp %f, got %f", tt.timestamp, data.Timestamp)
+			}
+		})
+	}
+}
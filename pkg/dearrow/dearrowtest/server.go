@@ -0,0 +1,188 @@
+// Package dearrowtest provides a fake DeArrow API server for tests.
+package dearrowtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Response is a scripted response of the fake server.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+	// Delay postpones the response, e.g. to trigger client timeouts.
+	Delay time.Duration
+}
+
+// JSON returns a 200 response with the value encoded as JSON.
+func JSON(v any) Response {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return Response{
+		Status: http.StatusOK,
+		Header: http.Header{"Content-Type": {"application/json"}},
+		Body:   body,
+	}
+}
+
+// Status returns an empty response with the status code.
+func Status(status int) Response {
+	return Response{Status: status}
+}
+
+// ThumbnailFailure returns a failed thumbnail response with the reason in the X-Failure-Reason header.
+func ThumbnailFailure(status int, reason string) Response {
+	return Response{
+		Status: status,
+		Header: http.Header{"X-Failure-Reason": {reason}},
+	}
+}
+
+// BrandingNotFound returns the 404 response the branding API sends for videos without any submissions.
+func BrandingNotFound() Response {
+	rs := JSON(map[string]any{
+		"titles":        []any{},
+		"thumbnails":    []any{},
+		"randomTime":    0,
+		"videoDuration": nil,
+	})
+	rs.Status = http.StatusNotFound
+	return rs
+}
+
+// Server serves scripted branding and thumbnail responses on both API paths, so its URL can be used as the
+// branding and thumbnail API URL at the same time. Videos without a script get a 404.
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	branding   map[string][]Response
+	thumbnails map[string][]Response
+	oEmbeds    map[string][]Response
+	requests   map[string]int
+	headers    map[string][]http.Header
+}
+
+func NewServer() *Server {
+	s := &Server{
+		branding:   make(map[string][]Response),
+		thumbnails: make(map[string][]Response),
+		oEmbeds:    make(map[string][]Response),
+		requests:   make(map[string]int),
+		headers:    make(map[string][]http.Header),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/branding", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		s.serve(w, r, s.branding, brandingKey(query.Get("videoID"), query.Get("locale")), "branding", BrandingNotFound())
+	})
+	mux.HandleFunc("GET /api/v1/getThumbnail", func(w http.ResponseWriter, r *http.Request) {
+		s.serve(w, r, s.thumbnails, r.URL.Query().Get("videoID"), "thumbnail", ThumbnailFailure(http.StatusNotFound, "Not found"))
+	})
+	mux.HandleFunc("GET /oembed", func(w http.ResponseWriter, r *http.Request) {
+		videoURL, err := url.Parse(r.URL.Query().Get("url"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.serve(w, r, s.oEmbeds, videoURL.Query().Get("v"), "oembed", Status(http.StatusNotFound))
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// SetBranding scripts the branding responses of the video. Each request consumes one response, the last one
+// is repeated for all further requests.
+func (s *Server) SetBranding(videoID string, responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.branding[videoID] = responses
+}
+
+// SetLocalizedBranding scripts the branding responses of the video in the locale the same way as SetBranding.
+// Requests in a locale without a script get a 404.
+func (s *Server) SetLocalizedBranding(videoID string, locale string, responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.branding[brandingKey(videoID, locale)] = responses
+}
+
+// SetThumbnail scripts the thumbnail responses of the video the same way as SetBranding.
+func (s *Server) SetThumbnail(videoID string, responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.thumbnails[videoID] = responses
+}
+
+// SetOEmbed scripts the oEmbed responses of the video the same way as SetBranding. The server only answers oEmbed
+// requests sent to its host in place of YouTube's.
+func (s *Server) SetOEmbed(videoID string, responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.oEmbeds[videoID] = responses
+}
+
+// BrandingRequests returns how many branding requests have been made for the video, in any locale.
+func (s *Server) BrandingRequests(videoID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests["branding/"+videoID]
+}
+
+// ThumbnailRequests returns how many thumbnail requests have been made for the video.
+func (s *Server) ThumbnailRequests(videoID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests["thumbnail/"+videoID]
+}
+
+// ThumbnailHeaders returns the headers of all thumbnail requests made for the video, in order.
+func (s *Server) ThumbnailHeaders(videoID string) []http.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.headers["thumbnail/"+videoID])
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, scripts map[string][]Response, key string, kind string, fallback Response) {
+	videoID := r.URL.Query().Get("videoID")
+
+	s.mu.Lock()
+	s.requests[kind+"/"+videoID]++
+	s.headers[kind+"/"+videoID] = append(s.headers[kind+"/"+videoID], r.Header.Clone())
+	rs := fallback
+	if responses := scripts[key]; len(responses) != 0 {
+		rs = responses[0]
+		if len(responses) > 1 {
+			scripts[key] = responses[1:]
+		}
+	}
+	s.mu.Unlock()
+
+	if rs.Delay > 0 {
+		select {
+		case <-time.After(rs.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	for key, values := range rs.Header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(rs.Status)
+	_, _ = w.Write(rs.Body)
+}
+
+func brandingKey(videoID string, locale string) string {
+	if locale == "" {
+		return videoID
+	}
+	return videoID + "/" + locale
+}
@@ -0,0 +1,147 @@
+package dearrow
+
+import (
+	"dearrow-bot/pkg/config"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// titleCaseSmallWords aren't capitalized in title case unless they start or end a sentence, like in the extension
+	titleCaseSmallWords = map[string]struct{}{
+		"a": {}, "an": {}, "the": {},
+		"and": {}, "but": {}, "or": {}, "nor": {}, "for": {}, "yet": {}, "so": {},
+		"as": {}, "at": {}, "by": {}, "in": {}, "of": {}, "off": {}, "on": {}, "per": {}, "to": {}, "up": {},
+		"via": {}, "vs": {}, "vs.": {}, "from": {}, "into": {}, "onto": {}, "over": {}, "with": {}, "than": {},
+	}
+)
+
+// FormatTitle formats the title like the extension does. Words with custom capitalization, e.g. acronyms like "NASA"
+// or names like "iPhone", are kept as they are. If most of the title is written in all caps, only short words like "PC"
+// are kept, as they're likely acronyms.
+func FormatTitle(title string, format config.TitleFormat) string {
+	switch format {
+	case config.TitleFormatNone:
+		return title
+	case config.TitleFormatFirstLetterUppercase:
+		return capitalize(title)
+	}
+
+	words := strings.Split(title, " ")
+	allCaps := isMostlyAllCaps(words)
+	last := -1
+	for i, word := range words {
+		if word != "" {
+			last = i
+		}
+	}
+	sentenceStart := true
+	for i, word := range words {
+		if !hasLetter(word) { // e.g. emojis or separators
+			continue
+		}
+		startsSentence := sentenceStart
+		sentenceStart = strings.ContainsAny(word[len(word)-1:], ".!?:")
+		lower := strings.ToLower(word)
+		if (allCaps && isShortAcronym(word, lower)) || (!allCaps && hasCustomCapitalization(word)) {
+			continue
+		}
+		switch format {
+		case config.TitleFormatCapitalizeWords:
+			words[i] = capitalize(lower)
+		case config.TitleFormatTitleCase:
+			if _, small := titleCaseSmallWords[lower]; small && !startsSentence && i != last {
+				words[i] = lower
+			} else {
+				words[i] = capitalize(lower)
+			}
+		case config.TitleFormatSentenceCase:
+			if startsSentence || isPronounI(lower) {
+				words[i] = capitalize(lower)
+			} else {
+				words[i] = lower
+			}
+		case config.TitleFormatLowerCase:
+			words[i] = lower
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// capitalize converts the first letter of s to upper case, skipping leading characters like quotes or brackets.
+func capitalize(s string) string {
+	for i, r := range s {
+		if unicode.IsLetter(r) {
+			return s[:i] + string(unicode.ToUpper(r)) + s[i+utf8.RuneLen(r):]
+		}
+	}
+	return s
+}
+
+// hasCustomCapitalization checks whether the word is written in all caps or has upper case letters after its first
+// letter.
+func hasCustomCapitalization(word string) bool {
+	letters := 0
+	for _, r := range word {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if letters > 1 && unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// isShortAcronym checks whether the word is written in all caps and has 2 or 3 letters, excluding small words like
+// "THE", which the extension treats as acronyms in all caps titles.
+func isShortAcronym(word string, lower string) bool {
+	if _, small := titleCaseSmallWords[strings.Trim(lower, ".,:;!?")]; small {
+		return false
+	}
+	letters := 0
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			if !unicode.IsUpper(r) {
+				return false
+			}
+			letters++
+		}
+	}
+	return letters >= 2 && letters <= 3
+}
+
+// isMostlyAllCaps checks whether more than half of the words with more than one letter are written in all caps.
+func isMostlyAllCaps(words []string) bool {
+	total, allCaps := 0, 0
+	for _, word := range words {
+		letters, upper := 0, 0
+		for _, r := range word {
+			if unicode.IsLetter(r) {
+				letters++
+				if unicode.IsUpper(r) {
+					upper++
+				}
+			}
+		}
+		if letters < 2 {
+			continue
+		}
+		total++
+		if letters == upper {
+			allCaps++
+		}
+	}
+	return total != 0 && allCaps*2 > total
+}
+
+func hasLetter(word string) bool {
+	return strings.IndexFunc(word, unicode.IsLetter) != -1
+}
+
+// isPronounI checks whether the lower case word is "i" or one of its contractions, which are always capitalized.
+func isPronounI(lower string) bool {
+	return lower == "i" || strings.HasPrefix(lower, "i'") || strings.HasPrefix(lower, "i’")
+}
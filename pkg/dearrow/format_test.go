@@ -0,0 +1,40 @@
+package dearrow_test
+
+import (
+	"dearrow-bot/pkg/config"
+	"dearrow-bot/pkg/dearrow"
+	"testing"
+)
+
+func TestFormatTitle(t *testing.T) {
+	tests := []struct {
+		name   string
+		title  string
+		format config.TitleFormat
+		want   string
+	}{
+		{"none", "the BEST iPhone video", config.TitleFormatNone, "the BEST iPhone video"},
+		{"capitalize words", "how to build a PC in 2024", config.TitleFormatCapitalizeWords, "How To Build A PC In 2024"},
+		{"title case", "how to build a PC in 2024", config.TitleFormatTitleCase, "How to Build a PC in 2024"},
+		{"title case last small word", "what is this made of", config.TitleFormatTitleCase, "What Is This Made Of"},
+		{"title case after colon", "minecraft: the end of an era", config.TitleFormatTitleCase, "Minecraft: The End of an Era"},
+		{"sentence case", "How To Build A PC In 2024", config.TitleFormatSentenceCase, "How to build a PC in 2024"},
+		{"sentence case pronoun", "Why I'm Leaving YouTube", config.TitleFormatSentenceCase, "Why I'm leaving YouTube"},
+		{"sentence case sentences", "It Works. Or Does It?", config.TitleFormatSentenceCase, "It works. Or does it?"},
+		{"sentence case leading emoji", "🔥 The Best Video", config.TitleFormatSentenceCase, "🔥 The best video"},
+		{"lower case", "The NASA Launch Explained", config.TitleFormatLowerCase, "the NASA launch explained"},
+		{"first letter uppercase", "the NASA launch Explained", config.TitleFormatFirstLetterUppercase, "The NASA launch Explained"},
+		{"first letter after quote", "\"quoted\" title", config.TitleFormatFirstLetterUppercase, "\"Quoted\" title"},
+		{"all caps title", "I BUILT THE WORLD'S BIGGEST PC", config.TitleFormatSentenceCase, "I built the world's biggest PC"},
+		{"all caps title case", "I BUILT THE WORLD'S BIGGEST PC", config.TitleFormatTitleCase, "I Built the World's Biggest PC"},
+		{"all caps title small words", "THE BIGGEST PC IN THE WORLD", config.TitleFormatSentenceCase, "The biggest PC in the world"},
+		{"few all caps words", "This Is INSANE", config.TitleFormatSentenceCase, "This is INSANE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dearrow.FormatTitle(tt.title, tt.format); got != tt.want {
+				t.Errorf("FormatTitle(%q, %d) = %q, want %q", tt.title, tt.format, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package dearrow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/json"
+)
+
+const (
+	oEmbedURL    = "https://www.youtube.com/oembed?format=json&url=%s"
+	videoURL     = "https://www.youtube.com/watch?v="
+	youtubeColor = 0xFF0000
+)
+
+type oEmbedResponse struct {
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name"`
+	AuthorURL    string `json:"author_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// FetchEmbed builds the embed of a video from its oEmbed data. It's used when Discord's own embed isn't available,
+// e.g. when the embeds of an edited message are suppressed.
+func (c *Client) FetchEmbed(ctx context.Context, videoID string) (*discord.Embed, error) {
+	link := videoURL + videoID
+	rq, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(oEmbedURL, url.QueryEscape(link)), nil)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := c.brandingClient.Do(rq)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Body.Close()
+	if rs.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received an unexpected code from an oembed response: %d", rs.StatusCode)
+	}
+	var oEmbed oEmbedResponse
+	if err := json.NewDecoder(rs.Body).Decode(&oEmbed); err != nil {
+		return nil, err
+	}
+	return &discord.Embed{
+		Title: oEmbed.Title,
+		URL:   link,
+		Color: youtubeColor,
+		Author: &discord.EmbedAuthor{
+			Name: oEmbed.AuthorName,
+			URL:  oEmbed.AuthorURL,
+		},
+		Thumbnail: &discord.EmbedResource{
+			URL: oEmbed.ThumbnailURL,
+		},
+		Provider: &discord.EmbedProvider{
+			Name: "YouTube",
+			URL:  "https://www.youtube.com",
+		},
+	}, nil
+}
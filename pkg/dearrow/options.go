@@ -0,0 +1,64 @@
+package dearrow
+
+import "time"
+
+const (
+	DefaultBrandingAPIURL  = "https://sponsor.ajay.app"
+	DefaultThumbnailAPIURL = "https://dearrow-thumb.ajay.app"
+
+	DefaultBrandingCacheSize        = 4096
+	DefaultBrandingCacheTTL         = 5 * time.Minute
+	DefaultBrandingCacheNotFoundTTL = time.Minute
+	DefaultThumbnailCacheSize       = 64 << 20 // 64 MiB
+)
+
+type Option func(*Client)
+
+// WithAPIURLs configures the base URLs of the branding and thumbnail APIs, e.g. to use a mirror or a local
+// instance. Empty URLs keep the defaults.
+func WithAPIURLs(brandingAPIURL string, thumbnailAPIURL string) Option {
+	return func(c *Client) {
+		if brandingAPIURL != "" {
+			c.brandingAPIURL = brandingAPIURL
+		}
+		if thumbnailAPIURL != "" {
+			c.thumbnailAPIURL = thumbnailAPIURL
+		}
+	}
+}
+
+// WithBrandingCache configures the branding cache. Responses for videos without any submissions are cached for
+// notFoundTTL, all other responses for ttl. A ttl of 0 doesn't cache the respective responses, and a size of 0 disables
+// the cache.
+func WithBrandingCache(size int, ttl time.Duration, notFoundTTL time.Duration) Option {
+	return func(c *Client) {
+		c.brandingCacheSize = size
+		c.brandingCacheTTL = ttl
+		c.brandingCacheNotFoundTTL = notFoundTTL
+	}
+}
+
+// WithThumbnailCache configures the thumbnail cache. Thumbnails are kept in memory, or in dir if it's not empty,
+// until they exceed size bytes. A size of 0 disables the cache.
+func WithThumbnailCache(size int, dir string) Option {
+	return func(c *Client) {
+		c.thumbnailCacheSize = size
+		c.thumbnailCacheDir = dir
+	}
+}
+
+// WithRetryPolicy configures how failed requests to the DeArrow APIs are retried.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithCircuitBreaker configures the circuit breakers of the DeArrow APIs. Each breaker opens after threshold
+// consecutive failures and lets a trial request through after the cooldown. A threshold of 0 disables the breakers.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		c.breakerThreshold = threshold
+		c.breakerCooldown = cooldown
+	}
+}
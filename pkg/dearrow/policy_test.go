@@ -0,0 +1,146 @@
+package dearrow_test
+
+import (
+	"dearrow-bot/pkg/config"
+	"dearrow-bot/pkg/dearrow"
+	"dearrow-bot/pkg/i18n"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+type submission struct {
+	votes    int
+	locked   bool
+	original bool
+}
+
+var submissions = map[string]submission{
+	"unvoted":               {votes: 0},
+	"voted":                 {votes: 3},
+	"downvoted":             {votes: -1},
+	"locked":                {votes: 0, locked: true},
+	"voted locked":          {votes: 3, locked: true},
+	"original":              {votes: 3, original: true},
+	"locked original":       {votes: 0, locked: true, original: true},
+	"voted locked original": {votes: 3, locked: true, original: true},
+}
+
+func TestPolicies(t *testing.T) {
+	tests := []struct {
+		policy   config.Policy
+		accepted []string
+	}{
+		{
+			policy:   config.Policy{},
+			accepted: []string{"unvoted", "voted", "locked", "voted locked", "locked original", "voted locked original"},
+		},
+		{
+			policy:   config.Policy{MinVotes: 2},
+			accepted: []string{"voted", "voted locked", "voted locked original"},
+		},
+		{
+			policy:   config.Policy{LockedOnly: true},
+			accepted: []string{"locked", "voted locked", "locked original", "voted locked original"},
+		},
+		{
+			policy:   config.Policy{RequireNonOriginal: true},
+			accepted: []string{"unvoted", "voted", "locked", "voted locked"},
+		},
+		{
+			policy:   config.Policy{MinVotes: 2, LockedOnly: true},
+			accepted: []string{"voted locked", "voted locked original"},
+		},
+		{
+			policy:   config.Policy{MinVotes: 2, RequireNonOriginal: true},
+			accepted: []string{"voted", "voted locked"},
+		},
+		{
+			policy:   config.Policy{LockedOnly: true, RequireNonOriginal: true},
+			accepted: []string{"locked", "voted locked"},
+		},
+		{
+			policy:   config.Policy{MinVotes: 2, LockedOnly: true, RequireNonOriginal: true},
+			accepted: []string{"voted locked"},
+		},
+	}
+	embed := discord.Embed{
+		Title: "Original",
+		URL:   "https://www.youtube.com/watch?v=" + videoID,
+	}
+	for _, tt := range tests {
+		for name, s := range submissions {
+			accepted := slices.Contains(tt.accepted, name)
+			t.Run(fmt.Sprintf("%+v/%s/titles", tt.policy, name), func(t *testing.T) {
+				rs := policyBranding(t, map[string]any{
+					"titles": []map[string]any{
+						{"title": "Replaced title", "votes": s.votes, "locked": s.locked, "original": s.original},
+					},
+				})
+				cfg := config.Guild{
+					ThumbnailMode:           config.ThumbnailModeOriginal,
+					TitleMinVotes:           tt.policy.MinVotes,
+					TitleLockedOnly:         tt.policy.LockedOnly,
+					TitleRequireNonOriginal: tt.policy.RequireNonOriginal,
+				}
+				data := rs.ToReplacementData(videoID, cfg, i18n.DefaultLocale, embed, discardLogger)
+				if replaced := data != nil && data.ToEmbed().Title == "Replaced title"; replaced != accepted {
+					t.Errorf("expected title replaced to be %t, got %t", accepted, replaced)
+				}
+			})
+			t.Run(fmt.Sprintf("%+v/%s/thumbnails", tt.policy, name), func(t *testing.T) {
+				rs := policyBranding(t, map[string]any{
+					"thumbnails": []map[string]any{
+						{"timestamp": 12.5, "votes": s.votes, "locked": s.locked, "original": s.original},
+					},
+				})
+				cfg := config.Guild{
+					ThumbnailMinVotes:           tt.policy.MinVotes,
+					ThumbnailLockedOnly:         tt.policy.LockedOnly,
+					ThumbnailRequireNonOriginal: tt.policy.RequireNonOriginal,
+				}
+				data := rs.ToReplacementData(videoID, cfg, i18n.DefaultLocale, embed, discardLogger)
+				if replaced := data != nil && data.Timestamp == 12.5; replaced != accepted {
+					t.Errorf("expected thumbnail replaced to be %t, got %t", accepted, replaced)
+				}
+			})
+		}
+	}
+}
+
+func TestPolicyTargets(t *testing.T) {
+	rs := policyBranding(t, map[string]any{
+		"titles": []map[string]any{
+			{"title": "Replaced title", "votes": 1},
+		},
+		"thumbnails": []map[string]any{
+			{"timestamp": 12.5, "votes": 1},
+		},
+	})
+	embed := discord.Embed{Title: "Original"}
+
+	data := rs.ToReplacementData(videoID, config.Guild{TitleLockedOnly: true}, i18n.DefaultLocale, embed, discardLogger)
+	if data == nil || data.ToEmbed().Title != "Original" || data.Timestamp != 12.5 {
+		t.Errorf("expected only the thumbnail to be replaced, got %+v", data)
+	}
+	data = rs.ToReplacementData(videoID, config.Guild{ThumbnailLockedOnly: true}, i18n.DefaultLocale, embed, discardLogger)
+	if data == nil || data.ToEmbed().Title != "Replaced title" || data.Timestamp != -1 {
+		t.Errorf("expected only the title to be replaced, got %+v", data)
+	}
+}
+
+func policyBranding(t *testing.T, v map[string]any) *dearrow.BrandingResponse {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rs dearrow.BrandingResponse
+	if err := json.Unmarshal(b, &rs); err != nil {
+		t.Fatal(err)
+	}
+	return &rs
+}
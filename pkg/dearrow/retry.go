@@ -0,0 +1,119 @@
+package dearrow
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+)
+
+// RetryPolicy configures how failed requests are retried. Delays grow exponentially from BaseDelay up to MaxDelay
+// with full jitter, unless the server asks for a specific delay using the Retry-After header.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+}
+
+// delay returns how long to wait before the next attempt and whether it's worth waiting at all.
+func (p RetryPolicy) delay(attempt int, rs *http.Response) (time.Duration, bool) {
+	if rs != nil {
+		if retryAfter, ok := parseRetryAfter(rs.Header.Get("Retry-After")); ok {
+			return retryAfter, retryAfter <= p.MaxDelay
+		}
+	}
+	backoff := min(p.BaseDelay<<(attempt-1), p.MaxDelay)
+	if backoff <= 0 {
+		return 0, true
+	}
+	return rand.N(backoff), true
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+func isRetryable(rs *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return rs.StatusCode == http.StatusTooManyRequests || rs.StatusCode >= http.StatusInternalServerError
+}
+
+// get runs a GET request, retrying it according to the retry policy. Requests are rejected with ErrCircuitOpen
+// without reaching the server while the breaker is open. Requests cancelled by the context don't count as failures.
+func (c *Client) get(ctx context.Context, client *http.Client, breaker *circuitBreaker, url string) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		rq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil) // requests can't be sent twice
+		if err != nil {
+			return nil, err
+		}
+		if !breaker.allow() {
+			return nil, ErrCircuitOpen
+		}
+		rs, err := client.Do(rq)
+		if ctx.Err() != nil {
+			breaker.cancel()
+			if rs != nil {
+				rs.Body.Close()
+			}
+			return nil, ctx.Err()
+		}
+		if !isRetryable(rs, err) {
+			breaker.success()
+			return rs, nil
+		}
+		if err == nil && rs.StatusCode == http.StatusTooManyRequests { // rate limits don't mean the API is down
+			breaker.success()
+		} else {
+			breaker.failure()
+		}
+		if attempt >= c.retryPolicy.MaxAttempts {
+			return rs, err
+		}
+		delay, ok := c.retryPolicy.delay(attempt, rs)
+		if !ok {
+			return rs, err
+		}
+		if rs != nil {
+			_, _ = io.Copy(io.Discard, rs.Body)
+			rs.Body.Close()
+		}
+		slog.Debug("dearrow: retrying a request",
+			slog.String("url", url),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			slog.String("breaker", breaker.name),
+			slog.String("breaker.state", breaker.State().String()))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
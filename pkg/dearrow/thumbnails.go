@@ -0,0 +1,165 @@
+package dearrow
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lmittmann/tint"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	thumbnailExtension = ".webp"
+	// downloadTimeout bounds shared downloads, which outlive the calls that started them.
+	downloadTimeout = time.Minute
+)
+
+// thumbnailCache caches generated thumbnails by video ID and timestamp. Thumbnails are kept in memory, or on disk
+// if a directory is provided, up to the byte budget. It is safe for concurrent use.
+type thumbnailCache struct {
+	dir     string
+	entries *lruCache[string, thumbnailEntry]
+	group   singleflight.Group
+}
+
+type thumbnailEntry struct {
+	data []byte // nil if the thumbnail is stored on disk
+	size int
+}
+
+func newThumbnailCache(budget int, dir string) (*thumbnailCache, error) {
+	c := &thumbnailCache{
+		dir: dir,
+		entries: newLRUCache[string, thumbnailEntry](budget, func(entry thumbnailEntry) int {
+			return entry.size
+		}),
+	}
+	if dir == "" {
+		return c, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	c.entries.onEvict = func(key string, _ thumbnailEntry) {
+		if err := os.Remove(c.path(key)); err != nil && !os.IsNotExist(err) {
+			slog.Warn("dearrow: error while removing a cached thumbnail", slog.String("thumbnail.key", key), tint.Err(err))
+		}
+	}
+	return c, c.load()
+}
+
+// load indexes thumbnails stored on disk by a previous run, oldest first so that they're evicted first.
+func (c *thumbnailCache) load() error {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	files := make([]os.FileInfo, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || !strings.HasSuffix(dirEntry.Name(), thumbnailExtension) {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, info)
+	}
+	slices.SortFunc(files, func(a, b os.FileInfo) int {
+		return a.ModTime().Compare(b.ModTime())
+	})
+	for _, file := range files {
+		c.entries.set(strings.TrimSuffix(file.Name(), thumbnailExtension), thumbnailEntry{size: int(file.Size())}, 0)
+	}
+	return nil
+}
+
+func (c *thumbnailCache) path(key string) string {
+	return filepath.Join(c.dir, key+thumbnailExtension)
+}
+
+// fetch returns a new reader over the cached thumbnail, downloading it first if needed. Concurrent calls for the
+// same thumbnail share a single download. It isn't cancelled along with the call starting it, as others may still wait
+// for it, while each call stops waiting once its own context is done.
+func (c *thumbnailCache) fetch(ctx context.Context, videoID string, timestamp float64, download func(context.Context) (io.ReadCloser, error)) (io.ReadCloser, error) {
+	key := fmt.Sprintf("%s_%.5f", videoID, timestamp)
+	if entry, ok := c.entries.get(key); ok {
+		data, err := c.read(key, entry)
+		if err == nil {
+			return io.NopCloser(bytes.NewReader(data)), nil
+		}
+		slog.Warn("dearrow: error while reading a cached thumbnail", slog.String("thumbnail.key", key), tint.Err(err))
+	}
+	results := c.group.DoChan(key, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), downloadTimeout)
+		defer cancel()
+		body, err := download(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		c.store(key, data)
+		return data, nil
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-results:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return io.NopCloser(bytes.NewReader(result.Val.([]byte))), nil
+	}
+}
+
+func (c *thumbnailCache) read(key string, entry thumbnailEntry) ([]byte, error) {
+	if entry.data != nil {
+		return entry.data, nil
+	}
+	return os.ReadFile(c.path(key))
+}
+
+func (c *thumbnailCache) store(key string, data []byte) {
+	entry := thumbnailEntry{
+		data: data,
+		size: len(data),
+	}
+	if c.dir != "" {
+		if err := writeFile(c.path(key), data); err != nil {
+			slog.Warn("dearrow: error while caching a thumbnail", slog.String("thumbnail.key", key), tint.Err(err))
+			return
+		}
+		entry.data = nil
+	}
+	c.entries.set(key, entry, 0)
+}
+
+// writeFile writes the data to a temporary file first so that readers never see a partially written thumbnail.
+func writeFile(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
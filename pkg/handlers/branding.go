@@ -2,11 +2,15 @@ package handlers
 
 import (
 	"bytes"
+	"dearrow-bot/pkg/dearrow"
+	"dearrow-bot/pkg/i18n"
 	"dearrow-bot/pkg/util"
+	"errors"
 	"io"
 	"net/http"
 	"os"
 	"regexp"
+	"strings"
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/handler"
@@ -22,7 +26,11 @@ const (
 )
 
 func (h *Handler) HandleBrandingSlash(data discord.SlashCommandInteractionData, event *handler.CommandEvent) error {
-	videoID := videoIDRegex.FindString(data.String("video"))
+	video := strings.TrimSpace(data.String("video"))
+	videoID := util.ParseVideoURL(video)
+	if videoID == "" {
+		videoID = videoIDRegex.FindString(video)
+	}
 	hide, ok := data.OptBool("hide")
 	if !ok {
 		hide = true
@@ -34,9 +42,15 @@ func (h *Handler) HandleBrandingContext(data discord.MessageCommandInteractionDa
 	var videoID string
 
 	message := data.TargetMessage()
-	embeds := message.Embeds
-	if len(embeds) != 0 {
-		videoID = util.ParseVideoID(embeds[0])
+	for _, embed := range message.Embeds {
+		if videoID = util.ParseVideoID(embed); videoID != "" {
+			break
+		}
+	}
+	if videoID == "" {
+		if links := util.ParseContentLinks(message.Content); len(links) != 0 {
+			videoID = links[0].VideoID
+		}
 	}
 	if videoID == "" {
 		videoID = videoIDRegex.FindString(message.Content)
@@ -45,20 +59,24 @@ func (h *Handler) HandleBrandingContext(data discord.MessageCommandInteractionDa
 }
 
 func (h *Handler) handleBranding(event *handler.CommandEvent, videoID string, hide bool) error {
+	locale := event.Locale()
 	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
 	if videoID == "" {
-		return event.CreateMessage(messageCreate.WithContent("Cannot extract video ID from input."))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.BrandingInvalidInput)))
 	}
-	rs, err := h.Bot.Client.FetchBrandingRaw(videoID, true)
+	rs, err := h.Bot.Client.FetchBrandingRaw(event.Ctx, videoID, "", true)
 	if err != nil {
 		if os.IsTimeout(err) {
-			return event.CreateMessage(messageCreate.WithContent("DeArrow API failed to respond within 2 seconds."))
+			return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.BrandingTimeout)))
+		}
+		if errors.Is(err, dearrow.ErrCircuitOpen) {
+			return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.BrandingUnavailable)))
 		}
 		return err
 	}
 	status := rs.StatusCode
 	if status != http.StatusOK && status != http.StatusNotFound {
-		return event.CreateMessage(messageCreate.WithContentf("DeArrow API returned a non-OK code: **%d**", status))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.BrandingStatus, status)))
 	}
 	defer rs.Body.Close()
 	b, err := io.ReadAll(rs.Body)
@@ -71,7 +89,7 @@ func (h *Handler) handleBranding(event *handler.CommandEvent, videoID string, hi
 	}
 	content := "```json\n" + out.String() + "\n```"
 	if len(content) > lengthLimit {
-		return event.CreateMessage(messageCreate.WithContentf("Response is longer than **%d** chars (**%d**). See the full response [here](%s).", lengthLimit, len(content), rs.Request.URL))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.BrandingTooLong, lengthLimit, len(content), rs.Request.URL)))
 	}
 	embedBuilder := discord.NewEmbedBuilder()
 	embedBuilder.SetColor(0x001BFF)
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"dearrow-bot/pkg/config"
+	"dearrow-bot/pkg/i18n"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/lmittmann/tint"
+)
+
+func (h *Handler) HandleCasualModeCurrent(event *handler.CommandEvent) error {
+	guildID := *event.GuildID()
+	locale := event.Locale()
+	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
+	cfg, err := h.Bot.DB.GetGuildConfig(event.Ctx, guildID)
+	if err != nil {
+		slog.Error("dearrow: error while getting guild config", slog.Any("guild.id", guildID), tint.Err(err))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.ErrorGetGuildConfig)))
+	}
+	return event.CreateMessage(messageCreate.WithContent(casualModeContent(locale, cfg)))
+}
+
+func (h *Handler) HandleCasualModeSet(data discord.SlashCommandInteractionData, event *handler.CommandEvent) error {
+	guildID := *event.GuildID()
+	locale := event.Locale()
+	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
+	if enabled, ok := data.OptBool("enabled"); ok {
+		if err := h.Bot.DB.UpdateGuildCasualMode(event.Ctx, guildID, enabled); err != nil {
+			slog.Error("dearrow: error while updating casual mode", slog.Bool("enabled", enabled), slog.Any("guild.id", guildID), tint.Err(err))
+			return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.ErrorUpdateCasualMode)))
+		}
+	}
+	for _, category := range config.CasualCategories {
+		votes, ok := data.OptInt(string(category))
+		if !ok {
+			continue
+		}
+		votes = max(votes, 0) // 0 ignores the category
+		if err := h.Bot.DB.UpdateGuildCasualThreshold(event.Ctx, guildID, category, votes); err != nil {
+			slog.Error("dearrow: error while updating casual threshold", slog.Any("category", category), slog.Int("votes", votes), slog.Any("guild.id", guildID), tint.Err(err))
+			return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.ErrorUpdateCasualMode)))
+		}
+	}
+	return h.HandleCasualModeCurrent(event)
+}
+
+func casualModeContent(locale discord.Locale, cfg config.Guild) string {
+	state := i18n.T(locale, i18n.StateDisabled)
+	if cfg.CasualMode {
+		state = i18n.T(locale, i18n.StateEnabled)
+	}
+	var sb strings.Builder
+	sb.WriteString(i18n.T(locale, i18n.CasualHeader, state))
+	for _, category := range config.CasualCategories {
+		threshold := cfg.CasualThreshold(category)
+		if threshold == 0 {
+			fmt.Fprintf(&sb, "\n- %s: %s", i18n.Enum(locale, category), i18n.T(locale, i18n.CasualIgnored))
+			continue
+		}
+		fmt.Fprintf(&sb, "\n- %s: %s", i18n.Enum(locale, category), i18n.T(locale, i18n.CasualVotes, threshold))
+	}
+	return sb.String()
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"dearrow-bot/pkg/config"
+	"dearrow-bot/pkg/i18n"
+	"dearrow-bot/pkg/util"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lmittmann/tint"
+)
+
+func (h *Handler) HandleChannelConfigSet(data discord.SlashCommandInteractionData, event *handler.CommandEvent) error {
+	channelID := optChannelID(data, event)
+	override := config.Channel{
+		ChannelID: channelID,
+	}
+	if enabled, ok := data.OptBool("enabled"); ok {
+		override.Enabled = &enabled
+	}
+	if mode, ok := data.OptInt("thumbnails"); ok {
+		override.ThumbnailMode = new(config.ThumbnailMode(mode))
+	}
+	if mode, ok := data.OptInt("titles"); ok {
+		override.OriginalTitleMode = new(config.OriginalTitleMode(mode))
+	}
+	locale := event.Locale()
+	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
+	if _, ok := data.OptString("language"); ok {
+		language, ok := optLocale(data)
+		if !ok {
+			return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.LanguageInvalid)))
+		}
+		override.Locale = &language
+	}
+	if override.IsEmpty() {
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.ChannelNoSettings)))
+	}
+	guildID := *event.GuildID()
+	if err := h.Bot.DB.UpdateChannelConfig(event.Ctx, guildID, override); err != nil {
+		slog.Error("dearrow: error while updating channel config", slog.Any("guild.id", guildID), slog.Any("channel.id", channelID), tint.Err(err))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.ErrorUpdateChannelConfig)))
+	}
+	return h.channelConfigView(event, channelID, i18n.T(locale, i18n.ChannelUpdated, discord.ChannelMention(channelID)))
+}
+
+func (h *Handler) HandleChannelConfigView(data discord.SlashCommandInteractionData, event *handler.CommandEvent) error {
+	return h.channelConfigView(event, optChannelID(data, event), "")
+}
+
+func (h *Handler) HandleChannelConfigClear(data discord.SlashCommandInteractionData, event *handler.CommandEvent) error {
+	channelID := optChannelID(data, event)
+	guildID := *event.GuildID()
+	locale := event.Locale()
+	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
+	ok, err := h.Bot.DB.DeleteChannelConfig(event.Ctx, guildID, channelID)
+	if err != nil {
+		slog.Error("dearrow: error while clearing channel config", slog.Any("guild.id", guildID), slog.Any("channel.id", channelID), tint.Err(err))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.ErrorClearChannelConfig)))
+	}
+	if !ok {
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.ChannelNoOverrides, discord.ChannelMention(channelID))))
+	}
+	return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.ChannelCleared, discord.ChannelMention(channelID))))
+}
+
+func (h *Handler) channelConfigView(event *handler.CommandEvent, channelID snowflake.ID, header string) error {
+	guildID := *event.GuildID()
+	locale := event.Locale()
+	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
+	guildCfg, err := h.Bot.DB.GetGuildConfig(event.Ctx, guildID)
+	if err != nil {
+		slog.Error("dearrow: error while getting guild config", slog.Any("guild.id", guildID), tint.Err(err))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.ErrorGetGuildConfig)))
+	}
+	chain := util.ChannelChain(event.Client().Caches, channelID)
+	overrides, err := h.Bot.DB.GetChannelConfigs(event.Ctx, guildID, chain)
+	if err != nil {
+		slog.Error("dearrow: error while getting channel config", slog.Any("guild.id", guildID), slog.Any("channel.id", channelID), tint.Err(err))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.ErrorGetChannelConfig)))
+	}
+	cfg, enabled := config.Resolve(guildCfg, chain, overrides)
+	// source returns where the setting comes from, i.e. the most specific channel overriding it
+	source := func(isSet func(config.Channel) bool) string {
+		for _, id := range chain {
+			for _, override := range overrides {
+				if override.ChannelID == id && isSet(override) {
+					if id == channelID {
+						return i18n.T(locale, i18n.ChannelSourceHere)
+					}
+					return i18n.T(locale, i18n.ChannelSourceInherits, discord.ChannelMention(id))
+				}
+			}
+		}
+		return i18n.T(locale, i18n.ChannelSourceGuild)
+	}
+	state := i18n.T(locale, i18n.StateDisabled)
+	if enabled {
+		state = i18n.T(locale, i18n.StateEnabled)
+	}
+
+	var sb strings.Builder
+	if header != "" {
+		sb.WriteString(header + "\n")
+	}
+	sb.WriteString(i18n.T(locale, i18n.ChannelHeader, discord.ChannelMention(channelID)) + "\n")
+	fmt.Fprintf(&sb, "- DeArrow: **%s** (%s)\n", state, source(func(c config.Channel) bool { return c.Enabled != nil }))
+	fmt.Fprintf(&sb, "- %s: **%s** (%s)\n", i18n.T(locale, i18n.LabelThumbnails), i18n.Enum(locale, cfg.ThumbnailMode), source(func(c config.Channel) bool { return c.ThumbnailMode != nil }))
+	fmt.Fprintf(&sb, "- %s: **%s** (%s)\n", i18n.T(locale, i18n.LabelTitles), i18n.Enum(locale, cfg.OriginalTitleMode), source(func(c config.Channel) bool { return c.OriginalTitleMode != nil }))
+	fmt.Fprintf(&sb, "- %s: **%s** (%s)", i18n.T(locale, i18n.LabelLanguage), localeName(locale, cfg.Locale), source(func(c config.Channel) bool { return c.Locale != nil }))
+	return event.CreateMessage(messageCreate.WithContent(sb.String()))
+}
+
+// optChannelID returns the channel from the command options, falling back to the channel the command was used in.
+func optChannelID(data discord.SlashCommandInteractionData, event *handler.CommandEvent) snowflake.ID {
+	if channel, ok := data.OptChannel("channel"); ok {
+		return channel.ID
+	}
+	return event.Channel().ID()
+}
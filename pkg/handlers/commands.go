@@ -1,21 +1,47 @@
 package handlers
 
 import (
+	"context"
 	"dearrow-bot/pkg"
+	"dearrow-bot/pkg/i18n"
 	"log/slog"
+	"time"
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/handler"
 	"github.com/lmittmann/tint"
 )
 
-func NewHandler(b *pkg.Bot, c *pkg.Config) *Handler {
+// interactionTimeout bounds the work done for an interaction, as Discord rejects responses after 3 seconds.
+const interactionTimeout = 3 * time.Second
+
+// NewHandler creates the handler of all interactions. Work done for interactions is cancelled along with the context.
+func NewHandler(ctx context.Context, b *pkg.Bot, c *pkg.Config) *Handler {
 	mux := handler.New()
+	mux.DefaultContext(func() context.Context {
+		return ctx
+	})
+	mux.Use(func(next handler.Handler) handler.Handler {
+		return func(e *handler.InteractionEvent) error {
+			ctx, cancel := context.WithTimeout(e.Ctx, interactionTimeout)
+			defer cancel()
+			e.Ctx = ctx
+			return next(e)
+		}
+	})
 	mux.Error(func(e *handler.InteractionEvent, err error) {
-		i := e.Interaction.(discord.ApplicationCommandInteraction)
-		slog.Error("dearrow: error while handling a command", slog.String("command.name", i.Data.CommandName()), tint.Err(err))
+		key := i18n.ErrorCommand
+		switch i := e.Interaction.(type) {
+		case discord.ApplicationCommandInteraction:
+			slog.Error("dearrow: error while handling a command", slog.String("command.name", i.Data.CommandName()), tint.Err(err))
+		case discord.ComponentInteraction:
+			slog.Error("dearrow: error while handling a component", slog.String("component.id", i.Data.CustomID()), tint.Err(err))
+			key = i18n.ErrorComponent
+		default:
+			slog.Error("dearrow: error while handling an interaction", slog.Any("interaction.type", e.Type()), tint.Err(err))
+		}
 		_ = e.Respond(discord.InteractionResponseTypeCreateMessage, discord.NewMessageCreate().
-			WithContentf("There was an error while handling the command: %v", err).
+			WithContent(i18n.T(e.Locale(), key, err)).
 			WithEphemeral(true))
 	})
 	handlers := &Handler{
@@ -37,6 +63,61 @@ func NewHandler(b *pkg.Bot, c *pkg.Config) *Handler {
 					r.SlashCommand("/set", handlers.HandleOriginalTitleModeSet)
 				})
 			})
+			r.Group(func(r handler.Router) {
+				r.Route("/formatting", func(r handler.Router) {
+					r.Command("/current", handlers.HandleTitleFormatCurrent)
+					r.SlashCommand("/set", handlers.HandleTitleFormatSet)
+				})
+			})
+			r.Group(func(r handler.Router) {
+				r.Route("/language", func(r handler.Router) {
+					r.Command("/current", handlers.HandleLanguageCurrent)
+					r.SlashCommand("/set", handlers.HandleLanguageSet)
+				})
+			})
+			r.Group(func(r handler.Router) {
+				r.Route("/links", func(r handler.Router) {
+					r.Command("/current", handlers.HandleLinkModeCurrent)
+					r.SlashCommand("/set", handlers.HandleLinkModeSet)
+				})
+			})
+			r.Group(func(r handler.Router) {
+				r.Route("/policy", func(r handler.Router) {
+					r.Command("/current", handlers.HandlePolicyCurrent)
+					r.SlashCommand("/set", handlers.HandlePolicySet)
+				})
+			})
+			r.Group(func(r handler.Router) {
+				r.Route("/casual", func(r handler.Router) {
+					r.Command("/current", handlers.HandleCasualModeCurrent)
+					r.SlashCommand("/set", handlers.HandleCasualModeSet)
+				})
+			})
+			r.Group(func(r handler.Router) {
+				r.Route("/status", func(r handler.Router) {
+					r.Command("/current", handlers.HandleStatusCurrent)
+					r.SlashCommand("/set", handlers.HandleStatusSet)
+				})
+			})
+			r.Group(func(r handler.Router) {
+				r.Route("/allowlist", func(r handler.Router) {
+					r.SlashCommand("/add", handlers.HandleAllowlistAdd)
+					r.SlashCommand("/remove", handlers.HandleAllowlistRemove)
+				})
+			})
+			r.Group(func(r handler.Router) {
+				r.Route("/denylist", func(r handler.Router) {
+					r.SlashCommand("/add", handlers.HandleDenylistAdd)
+					r.SlashCommand("/remove", handlers.HandleDenylistRemove)
+				})
+			})
+			r.Group(func(r handler.Router) {
+				r.Route("/channel", func(r handler.Router) {
+					r.SlashCommand("/set", handlers.HandleChannelConfigSet)
+					r.SlashCommand("/view", handlers.HandleChannelConfigView)
+					r.SlashCommand("/clear", handlers.HandleChannelConfigClear)
+				})
+			})
 		})
 	})
 	handlers.Group(func(r handler.Router) {
@@ -44,6 +125,11 @@ func NewHandler(b *pkg.Bot, c *pkg.Config) *Handler {
 		r.MessageCommand("/Fetch branding", handlers.HandleBrandingContext)
 	})
 	handlers.MessageCommand("/Delete embeds", handlers.HandleDeleteEmbeds)
+	handlers.Route("/preferences", func(r handler.Router) {
+		r.Command("/current", handlers.HandlePreferencesCurrent)
+		r.SlashCommand("/set", handlers.HandlePreferencesSet)
+	})
+	handlers.ButtonComponent(ShowOriginalButtonID, handlers.HandleShowOriginal)
 	return handlers
 }
 
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"dearrow-bot/pkg/config"
+	"dearrow-bot/pkg/i18n"
+	"path"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/omit"
+)
+
+var (
+	// configurableChannelTypes are the channels which can be configured, including categories passing their
+	// configuration on to their channels.
+	configurableChannelTypes = []discord.ChannelType{
+		discord.ChannelTypeGuildText,
+		discord.ChannelTypeGuildVoice,
+		discord.ChannelTypeGuildCategory,
+		discord.ChannelTypeGuildNews,
+		discord.ChannelTypeGuildNewsThread,
+		discord.ChannelTypeGuildPublicThread,
+		discord.ChannelTypeGuildPrivateThread,
+		discord.ChannelTypeGuildStageVoice,
+		discord.ChannelTypeGuildForum,
+		discord.ChannelTypeGuildMedia,
+	}
+	guildOnly = []discord.InteractionContextType{discord.InteractionContextTypeGuild}
+)
+
+// Commands returns the definitions of all commands routed by NewHandler. The paths used for their localizations
+// match the routes.
+func Commands() []discord.ApplicationCommandCreate {
+	configure := slashCommand("/configure",
+		modeGroup("/configure/thumbnails", enumOption("/configure/thumbnails/set/mode", true, config.ThumbnailModes)),
+		modeGroup("/configure/titles", enumOption("/configure/titles/set/mode", true, config.OriginalTitleModes)),
+		modeGroup("/configure/formatting", enumOption("/configure/formatting/set/mode", true, config.TitleFormats)),
+		modeGroup("/configure/language", stringOption("/configure/language/set/language", true)),
+		modeGroup("/configure/links", enumOption("/configure/links/set/mode", true, config.LinkModes)),
+		modeGroup("/configure/policy",
+			enumOption("/configure/policy/set/target", true, config.PolicyTargets),
+			intOption("/configure/policy/set/min-votes", false),
+			boolOption("/configure/policy/set/locked-only", false),
+			boolOption("/configure/policy/set/require-non-original", false)),
+		modeGroup("/configure/casual", casualOptions()...),
+		modeGroup("/configure/status", boolOption("/configure/status/set/enabled", true)),
+		listGroup("/configure/allowlist"),
+		listGroup("/configure/denylist"),
+		subCommandGroup("/configure/channel",
+			subCommand("/configure/channel/set",
+				channelOption("/configure/channel/set/channel", false),
+				boolOption("/configure/channel/set/enabled", false),
+				enumOption("/configure/channel/set/thumbnails", false, config.ThumbnailModes),
+				enumOption("/configure/channel/set/titles", false, config.OriginalTitleModes),
+				stringOption("/configure/channel/set/language", false)),
+			subCommand("/configure/channel/view", channelOption("/configure/channel/view/channel", false)),
+			subCommand("/configure/channel/clear", channelOption("/configure/channel/clear/channel", false))),
+	)
+	configure.DefaultMemberPermissions = omit.NewPtr(discord.PermissionManageGuild)
+	configure.Contexts = guildOnly
+
+	return []discord.ApplicationCommandCreate{
+		configure,
+		slashCommand("/branding",
+			stringOption("/branding/video", true),
+			boolOption("/branding/hide", false)),
+		discord.MessageCommandCreate{
+			Name:              "Fetch branding",
+			NameLocalizations: i18n.CommandName("/Fetch branding"),
+		},
+		discord.MessageCommandCreate{
+			Name:              "Delete embeds",
+			NameLocalizations: i18n.CommandName("/Delete embeds"),
+			Contexts:          guildOnly,
+		},
+		slashCommand("/preferences",
+			subCommand("/preferences/current"),
+			subCommand("/preferences/set", boolOption("/preferences/set/replies", true))),
+	}
+}
+
+// modeGroup returns the subcommand group of a setting with a current and a set subcommand taking the options.
+func modeGroup(p string, options ...discord.ApplicationCommandOption) discord.ApplicationCommandOptionSubCommandGroup {
+	return subCommandGroup(p, subCommand(p+"/current"), subCommand(p+"/set", options...))
+}
+
+// listGroup returns the subcommand group of an allow- or denylist with an add and a remove subcommand.
+func listGroup(p string) discord.ApplicationCommandOptionSubCommandGroup {
+	return subCommandGroup(p,
+		subCommand(p+"/add", channelOption(p+"/add/channel", false), roleOption(p+"/add/role", false)),
+		subCommand(p+"/remove", channelOption(p+"/remove/channel", false), roleOption(p+"/remove/role", false)))
+}
+
+func casualOptions() []discord.ApplicationCommandOption {
+	options := []discord.ApplicationCommandOption{boolOption("/configure/casual/set/enabled", false)}
+	for _, category := range config.CasualCategories {
+		option := intOption("/configure/casual/set/"+string(category), false)
+		option.MinValue = new(0)
+		options = append(options, option)
+	}
+	return options
+}
+
+func slashCommand(p string, options ...discord.ApplicationCommandOption) discord.SlashCommandCreate {
+	description, descriptionLocalizations := i18n.CommandDescription(p)
+	return discord.SlashCommandCreate{
+		Name:                     path.Base(p),
+		NameLocalizations:        i18n.CommandName(p),
+		Description:              description,
+		DescriptionLocalizations: descriptionLocalizations,
+		Options:                  options,
+	}
+}
+
+func subCommandGroup(p string, subCommands ...discord.ApplicationCommandOptionSubCommand) discord.ApplicationCommandOptionSubCommandGroup {
+	description, descriptionLocalizations := i18n.CommandDescription(p)
+	return discord.ApplicationCommandOptionSubCommandGroup{
+		Name:                     path.Base(p),
+		NameLocalizations:        i18n.CommandName(p),
+		Description:              description,
+		DescriptionLocalizations: descriptionLocalizations,
+		Options:                  subCommands,
+	}
+}
+
+func subCommand(p string, options ...discord.ApplicationCommandOption) discord.ApplicationCommandOptionSubCommand {
+	description, descriptionLocalizations := i18n.CommandDescription(p)
+	return discord.ApplicationCommandOptionSubCommand{
+		Name:                     path.Base(p),
+		NameLocalizations:        i18n.CommandName(p),
+		Description:              description,
+		DescriptionLocalizations: descriptionLocalizations,
+		Options:                  options,
+	}
+}
+
+func stringOption(p string, required bool) discord.ApplicationCommandOptionString {
+	description, descriptionLocalizations := i18n.CommandDescription(p)
+	return discord.ApplicationCommandOptionString{
+		Name:                     path.Base(p),
+		NameLocalizations:        i18n.CommandName(p),
+		Description:              description,
+		DescriptionLocalizations: descriptionLocalizations,
+		Required:                 required,
+	}
+}
+
+func intOption(p string, required bool) discord.ApplicationCommandOptionInt {
+	description, descriptionLocalizations := i18n.CommandDescription(p)
+	return discord.ApplicationCommandOptionInt{
+		Name:                     path.Base(p),
+		NameLocalizations:        i18n.CommandName(p),
+		Description:              description,
+		DescriptionLocalizations: descriptionLocalizations,
+		Required:                 required,
+	}
+}
+
+// enumOption returns an int option with a choice for each of the values, named by their localized description.
+func enumOption[T ~int](p string, required bool, values []T) discord.ApplicationCommandOptionInt {
+	option := intOption(p, required)
+	for _, v := range values {
+		choice := discord.ApplicationCommandOptionChoiceInt{
+			Name:  i18n.Enum(i18n.DefaultLocale, v),
+			Value: int(v),
+		}
+		if key, ok := i18n.EnumKey(v); ok {
+			choice.NameLocalizations = i18n.Localizations(key)
+		}
+		option.Choices = append(option.Choices, choice)
+	}
+	return option
+}
+
+func boolOption(p string, required bool) discord.ApplicationCommandOptionBool {
+	description, descriptionLocalizations := i18n.CommandDescription(p)
+	return discord.ApplicationCommandOptionBool{
+		Name:                     path.Base(p),
+		NameLocalizations:        i18n.CommandName(p),
+		Description:              description,
+		DescriptionLocalizations: descriptionLocalizations,
+		Required:                 required,
+	}
+}
+
+func channelOption(p string, required bool) discord.ApplicationCommandOptionChannel {
+	description, descriptionLocalizations := i18n.CommandDescription(p)
+	return discord.ApplicationCommandOptionChannel{
+		Name:                     path.Base(p),
+		NameLocalizations:        i18n.CommandName(p),
+		Description:              description,
+		DescriptionLocalizations: descriptionLocalizations,
+		Required:                 required,
+		ChannelTypes:             configurableChannelTypes,
+	}
+}
+
+func roleOption(p string, required bool) discord.ApplicationCommandOptionRole {
+	description, descriptionLocalizations := i18n.CommandDescription(p)
+	return discord.ApplicationCommandOptionRole{
+		Name:                     path.Base(p),
+		NameLocalizations:        i18n.CommandName(p),
+		Description:              description,
+		DescriptionLocalizations: descriptionLocalizations,
+		Required:                 required,
+	}
+}
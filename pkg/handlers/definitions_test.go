@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+var slashCommandNameRegex = regexp.MustCompile(`^[-_\p{Ll}\p{Lo}\p{N}]{1,32}$`)
+
+func TestCommands(t *testing.T) {
+	for _, command := range Commands() {
+		switch c := command.(type) {
+		case discord.SlashCommandCreate:
+			checkName(t, c.Name, c.NameLocalizations)
+			checkDescription(t, c.Name, c.Description, c.DescriptionLocalizations)
+			checkOptions(t, c.Name, c.Options)
+		case discord.MessageCommandCreate:
+			if c.Name == "" || utf8.RuneCountInString(c.Name) > 32 {
+				t.Errorf("invalid message command name %q", c.Name)
+			}
+		}
+	}
+}
+
+func checkOptions(t *testing.T, parent string, options []discord.ApplicationCommandOption) {
+	t.Helper()
+	if len(options) > 25 {
+		t.Errorf("%s: expected at most 25 options, got %d", parent, len(options))
+	}
+	for _, option := range options {
+		name := parent + " " + option.OptionName()
+		switch o := option.(type) {
+		case discord.ApplicationCommandOptionSubCommandGroup:
+			checkName(t, o.Name, o.NameLocalizations)
+			checkDescription(t, name, o.Description, o.DescriptionLocalizations)
+			subCommands := make([]discord.ApplicationCommandOption, len(o.Options))
+			for i, subCommand := range o.Options {
+				subCommands[i] = subCommand
+			}
+			checkOptions(t, name, subCommands)
+		case discord.ApplicationCommandOptionSubCommand:
+			checkName(t, o.Name, o.NameLocalizations)
+			checkDescription(t, name, o.Description, o.DescriptionLocalizations)
+			checkOptions(t, name, o.Options)
+		case discord.ApplicationCommandOptionInt:
+			checkName(t, o.Name, o.NameLocalizations)
+			checkDescription(t, name, o.Description, o.DescriptionLocalizations)
+			if len(o.Choices) > 25 {
+				t.Errorf("%s: expected at most 25 choices, got %d", name, len(o.Choices))
+			}
+			for _, choice := range o.Choices {
+				checkDescription(t, name, choice.Name, choice.NameLocalizations)
+			}
+		case discord.ApplicationCommandOptionString:
+			checkName(t, o.Name, o.NameLocalizations)
+			checkDescription(t, name, o.Description, o.DescriptionLocalizations)
+		case discord.ApplicationCommandOptionBool:
+			checkName(t, o.Name, o.NameLocalizations)
+			checkDescription(t, name, o.Description, o.DescriptionLocalizations)
+		case discord.ApplicationCommandOptionChannel:
+			checkName(t, o.Name, o.NameLocalizations)
+			checkDescription(t, name, o.Description, o.DescriptionLocalizations)
+		case discord.ApplicationCommandOptionRole:
+			checkName(t, o.Name, o.NameLocalizations)
+			checkDescription(t, name, o.Description, o.DescriptionLocalizations)
+		default:
+			t.Errorf("%s: unexpected option type %T", name, option)
+		}
+	}
+}
+
+func checkName(t *testing.T, name string, localizations map[discord.Locale]string) {
+	t.Helper()
+	for _, n := range append([]string{name}, slices.Collect(maps.Values(localizations))...) {
+		if !slashCommandNameRegex.MatchString(n) {
+			t.Errorf("invalid name %q", n)
+		}
+	}
+}
+
+func checkDescription(t *testing.T, name string, description string, localizations map[discord.Locale]string) {
+	t.Helper()
+	for _, d := range append([]string{description}, slices.Collect(maps.Values(localizations))...) {
+		if strings.HasPrefix(d, "command/") { // the key is returned for missing messages
+			t.Errorf("%s: missing description %q", name, d)
+		}
+		if d == "" || utf8.RuneCountInString(d) > 100 {
+			t.Errorf("%s: invalid description %q", name, d)
+		}
+	}
+}
@@ -1,32 +1,40 @@
 package handlers
 
 import (
+	"dearrow-bot/pkg/i18n"
+	"log/slog"
+
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/lmittmann/tint"
 )
 
 func (h *Handler) HandleDeleteEmbeds(data discord.MessageCommandInteractionData, event *handler.CommandEvent) error {
 	message := data.TargetMessage()
 	messageRef := message.MessageReference
+	locale := event.Locale()
 	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
 	if messageRef == nil || messageRef.MessageID == nil {
-		return event.CreateMessage(messageCreate.WithContent("Message is not a reply."))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.DeleteNotReply)))
 	}
 	if message.Author.ID != h.Config.DeArrowUserID {
-		return event.CreateMessage(messageCreate.WithContent("Message is not a DeArrow reply."))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.DeleteNotDeArrowReply)))
 	}
-	rest := event.Client().Rest
+	client := event.Client().Rest
 	parentID := *messageRef.MessageID
-	parent, err := rest.GetMessage(event.Channel().ID(), parentID)
+	parent, err := client.GetMessage(event.Channel().ID(), parentID, rest.WithCtx(event.Ctx))
 	if err != nil {
-		return event.CreateMessage(messageCreate.WithContent("Failed to fetch the parent message."))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.DeleteFetchFailed)))
 	}
 	if parent.Author.ID != event.User().ID {
-		return event.CreateMessage(messageCreate.WithContent("Only the message author can delete DeArrow embeds."))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.DeleteNotAuthor)))
 	}
-	if err := event.CreateMessage(messageCreate.WithContent("Deleting DeArrow embeds.")); err != nil {
+	if err := event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.DeleteDeleting))); err != nil {
 		return err
 	}
-	delete(h.Bot.ReplyMap, parentID) // remove parent from the map as the DeArrow reply is now gone
-	return rest.DeleteMessage(event.Channel().ID(), message.ID)
+	if _, _, err := h.Bot.Replies.Delete(event.Ctx, parentID); err != nil { // remove parent from the store as the DeArrow reply is now gone
+		slog.Error("dearrow: error while removing a reply", slog.Any("parent.id", parentID), tint.Err(err))
+	}
+	return client.DeleteMessage(event.Channel().ID(), message.ID, rest.WithCtx(event.Ctx))
 }
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"dearrow-bot/pkg/config"
+	"dearrow-bot/pkg/i18n"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lmittmann/tint"
+)
+
+func (h *Handler) HandleStatusCurrent(event *handler.CommandEvent) error {
+	guildID := *event.GuildID()
+	locale := event.Locale()
+	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
+	cfg, err := h.Bot.DB.GetGuildConfig(event.Ctx, guildID)
+	if err != nil {
+		slog.Error("dearrow: error while getting guild config", slog.Any("guild.id", guildID), tint.Err(err))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.ErrorGetGuildConfig)))
+	}
+	state := i18n.T(locale, i18n.StateEnabled)
+	if cfg.Disabled {
+		state = i18n.T(locale, i18n.StateDisabled)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(i18n.T(locale, i18n.StatusCurrent, state) + "\n")
+	writeList := func(l config.List, mention func(snowflake.ID) string) {
+		entries := cfg.Entries(l)
+		if len(entries) == 0 {
+			fmt.Fprintf(&sb, "- %s: %s\n", capitalize(i18n.Enum(locale, l)), i18n.T(locale, i18n.ListEmpty))
+			return
+		}
+		mentions := make([]string, len(entries))
+		for i, id := range entries {
+			mentions[i] = mention(id)
+		}
+		fmt.Fprintf(&sb, "- %s: %s\n", capitalize(i18n.Enum(locale, l)), strings.Join(mentions, ", "))
+	}
+	writeList(config.ListChannelAllow, discord.ChannelMention)
+	writeList(config.ListChannelDeny, discord.ChannelMention)
+	writeList(config.ListRoleAllow, discord.RoleMention)
+	writeList(config.ListRoleDeny, discord.RoleMention)
+	return event.CreateMessage(messageCreate.WithContent(strings.TrimSuffix(sb.String(), "\n")).
+		WithAllowedMentions(&discord.AllowedMentions{}))
+}
+
+func (h *Handler) HandleStatusSet(data discord.SlashCommandInteractionData, event *handler.CommandEvent) error {
+	guildID := *event.GuildID()
+	enabled := data.Bool("enabled")
+	locale := event.Locale()
+	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
+	if err := h.Bot.DB.UpdateGuildDisabled(event.Ctx, guildID, !enabled); err != nil {
+		slog.Error("dearrow: error while updating guild status", slog.Bool("enabled", enabled), slog.Any("guild.id", guildID), tint.Err(err))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.ErrorUpdateStatus)))
+	}
+	if enabled {
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.StatusEnabled)))
+	}
+	return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.StatusDisabled)))
+}
+
+func (h *Handler) HandleAllowlistAdd(data discord.SlashCommandInteractionData, event *handler.CommandEvent) error {
+	return h.listUpdateHandler(data, event, config.ListChannelAllow, config.ListRoleAllow, true)
+}
+
+func (h *Handler) HandleAllowlistRemove(data discord.SlashCommandInteractionData, event *handler.CommandEvent) error {
+	return h.listUpdateHandler(data, event, config.ListChannelAllow, config.ListRoleAllow, false)
+}
+
+func (h *Handler) HandleDenylistAdd(data discord.SlashCommandInteractionData, event *handler.CommandEvent) error {
+	return h.listUpdateHandler(data, event, config.ListChannelDeny, config.ListRoleDeny, true)
+}
+
+func (h *Handler) HandleDenylistRemove(data discord.SlashCommandInteractionData, event *handler.CommandEvent) error {
+	return h.listUpdateHandler(data, event, config.ListChannelDeny, config.ListRoleDeny, false)
+}
+
+// listUpdateHandler adds the channel and role from the command options to their lists, or removes them if add is false.
+func (h *Handler) listUpdateHandler(data discord.SlashCommandInteractionData, event *handler.CommandEvent, channelList config.List, roleList config.List, add bool) error {
+	guildID := *event.GuildID()
+	locale := event.Locale()
+	messageCreate := discord.NewMessageCreate().WithEphemeral(true).WithAllowedMentions(&discord.AllowedMentions{})
+	type entry struct {
+		list    config.List
+		id      snowflake.ID
+		mention string
+	}
+	var entries []entry
+	if channel, ok := data.OptChannel("channel"); ok {
+		entries = append(entries, entry{channelList, channel.ID, discord.ChannelMention(channel.ID)})
+	}
+	if role, ok := data.OptRole("role"); ok {
+		entries = append(entries, entry{roleList, role.ID, discord.RoleMention(role.ID)})
+	}
+	if len(entries) == 0 {
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.ListMissingEntry)))
+	}
+
+	var sb strings.Builder
+	for _, e := range entries {
+		update, message := h.Bot.DB.AddToGuildList, i18n.ListAdded
+		if !add {
+			update, message = h.Bot.DB.RemoveFromGuildList, i18n.ListRemoved
+		}
+		if err := update(event.Ctx, guildID, e.list, e.id); err != nil {
+			slog.Error("dearrow: error while updating guild list", slog.Any("list", e.list), slog.Any("id", e.id), slog.Any("guild.id", guildID), tint.Err(err))
+			return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.ErrorUpdateList, i18n.Enum(locale, e.list))))
+		}
+		sb.WriteString(i18n.T(locale, message, e.mention, i18n.Enum(locale, e.list)) + "\n")
+	}
+	return event.CreateMessage(messageCreate.WithContent(strings.TrimSuffix(sb.String(), "\n")))
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"dearrow-bot/pkg/config"
+	"dearrow-bot/pkg/i18n"
+	"log/slog"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/lmittmann/tint"
+)
+
+// defaultLocaleOption can be used instead of a locale to use the default submissions.
+const defaultLocaleOption = "default"
+
+func (h *Handler) HandleLanguageCurrent(event *handler.CommandEvent) error {
+	guildID := *event.GuildID()
+	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
+	cfg, err := h.Bot.DB.GetGuildConfig(event.Ctx, guildID)
+	if err != nil {
+		slog.Error("dearrow: error while getting guild config", slog.Any("guild.id", guildID), tint.Err(err))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.ErrorGetGuildConfig)))
+	}
+	return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.LanguageCurrent, localeName(event.Locale(), cfg.Locale))))
+}
+
+func (h *Handler) HandleLanguageSet(data discord.SlashCommandInteractionData, event *handler.CommandEvent) error {
+	guildID := *event.GuildID()
+	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
+	locale, ok := optLocale(data)
+	if !ok {
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.LanguageInvalid)))
+	}
+	if err := h.Bot.DB.UpdateGuildLocale(event.Ctx, guildID, locale); err != nil {
+		slog.Error("dearrow: error while updating language", slog.String("locale", locale), slog.Any("guild.id", guildID), tint.Err(err))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.ErrorUpdateLanguage)))
+	}
+	return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.LanguageSet, localeName(event.Locale(), locale))))
+}
+
+// optLocale returns the locale from the language option, which is empty for the default submissions if the option is
+// missing or set to defaultLocaleOption, and false if the locale is invalid.
+func optLocale(data discord.SlashCommandInteractionData) (string, bool) {
+	language := strings.TrimSpace(data.String("language"))
+	if language == "" || strings.EqualFold(language, defaultLocaleOption) {
+		return "", true
+	}
+	return language, config.IsValidLocale(language)
+}
+
+// localeName returns the name of the branding locale shown to users of the interaction locale.
+func localeName(interactionLocale discord.Locale, locale string) string {
+	if locale == "" {
+		return i18n.T(interactionLocale, i18n.LanguageDefault)
+	}
+	return locale
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"dearrow-bot/pkg/config"
+	"dearrow-bot/pkg/i18n"
+	"log/slog"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/lmittmann/tint"
+)
+
+func (h *Handler) HandleLinkModeCurrent(event *handler.CommandEvent) error {
+	return h.modeCurrentHandler(event, func(cfg config.Guild) any {
+		return cfg.LinkMode
+	})
+}
+
+func (h *Handler) HandleLinkModeSet(data discord.SlashCommandInteractionData, event *handler.CommandEvent) error {
+	linkMode := config.LinkMode(data.Int("mode"))
+	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
+	if err := h.Bot.DB.UpdateGuildLinkMode(event.Ctx, *event.GuildID(), linkMode); err != nil {
+		slog.Error("dearrow: error while updating link mode", slog.Any("mode", linkMode), slog.Any("guild.id", *event.GuildID()), tint.Err(err))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.ErrorUpdateLinkMode)))
+	}
+	return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.ModeSet, i18n.Enum(event.Locale(), linkMode))))
+}
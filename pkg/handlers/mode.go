@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"dearrow-bot/pkg/config"
+	"dearrow-bot/pkg/i18n"
 	"log/slog"
 
 	"github.com/disgoorg/disgo/discord"
@@ -9,12 +10,12 @@ import (
 	"github.com/lmittmann/tint"
 )
 
-func (h *Handler) modeCurrentHandler(event *handler.CommandEvent, modeFunc func(guild config.Guild) string) error {
-	cfg, err := h.Bot.DB.GetGuildConfig(*event.GuildID())
+func (h *Handler) modeCurrentHandler(event *handler.CommandEvent, modeFunc func(guild config.Guild) any) error {
+	cfg, err := h.Bot.DB.GetGuildConfig(event.Ctx, *event.GuildID())
 	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
 	if err != nil {
 		slog.Error("dearrow: error while getting guild config", slog.Any("guild.id", *event.GuildID()), tint.Err(err))
-		return event.CreateMessage(messageCreate.WithContent("There was an error while getting the guild configuration."))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.ErrorGetGuildConfig)))
 	}
-	return event.CreateMessage(messageCreate.WithContentf("Current mode is set to **%s**.", modeFunc(cfg)))
+	return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.ModeCurrent, i18n.Enum(event.Locale(), modeFunc(cfg)))))
 }
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"dearrow-bot/pkg/config"
+	"dearrow-bot/pkg/i18n"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/lmittmann/tint"
+)
+
+func (h *Handler) HandlePolicyCurrent(event *handler.CommandEvent) error {
+	guildID := *event.GuildID()
+	locale := event.Locale()
+	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
+	cfg, err := h.Bot.DB.GetGuildConfig(event.Ctx, guildID)
+	if err != nil {
+		slog.Error("dearrow: error while getting guild config", slog.Any("guild.id", guildID), tint.Err(err))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.ErrorGetGuildConfig)))
+	}
+	var sb strings.Builder
+	for i, target := range config.PolicyTargets {
+		if i != 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(policyContent(locale, target, cfg.Policy(target)))
+	}
+	return event.CreateMessage(messageCreate.WithContent(sb.String()))
+}
+
+func (h *Handler) HandlePolicySet(data discord.SlashCommandInteractionData, event *handler.CommandEvent) error {
+	guildID := *event.GuildID()
+	target := config.PolicyTarget(data.Int("target"))
+	locale := event.Locale()
+	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
+	cfg, err := h.Bot.DB.GetGuildConfig(event.Ctx, guildID)
+	if err != nil {
+		slog.Error("dearrow: error while getting guild config", slog.Any("guild.id", guildID), tint.Err(err))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.ErrorGetGuildConfig)))
+	}
+	policy := cfg.Policy(target)
+	if votes, ok := data.OptInt("min-votes"); ok {
+		policy.MinVotes = votes
+	}
+	if lockedOnly, ok := data.OptBool("locked-only"); ok {
+		policy.LockedOnly = lockedOnly
+	}
+	if requireNonOriginal, ok := data.OptBool("require-non-original"); ok {
+		policy.RequireNonOriginal = requireNonOriginal
+	}
+	if err := h.Bot.DB.UpdateGuildPolicy(event.Ctx, guildID, target, policy); err != nil {
+		slog.Error("dearrow: error while updating policy", slog.Any("target", target), slog.Any("policy", policy), slog.Any("guild.id", guildID), tint.Err(err))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(locale, i18n.ErrorUpdatePolicy)))
+	}
+	return event.CreateMessage(messageCreate.WithContent(policyContent(locale, target, policy)))
+}
+
+func policyContent(locale discord.Locale, target config.PolicyTarget, policy config.Policy) string {
+	yesNo := func(b bool) string {
+		if b {
+			return i18n.T(locale, i18n.Yes)
+		}
+		return i18n.T(locale, i18n.No)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**%s**:\n", i18n.Enum(locale, target))
+	fmt.Fprintf(&sb, "- %s: **%d**\n", i18n.T(locale, i18n.PolicyMinVotes), policy.MinVotes)
+	fmt.Fprintf(&sb, "- %s: **%s**\n", i18n.T(locale, i18n.PolicyLockedOnly), yesNo(policy.LockedOnly))
+	fmt.Fprintf(&sb, "- %s: **%s**", i18n.T(locale, i18n.PolicyRequireNonOriginal), yesNo(policy.RequireNonOriginal))
+	return sb.String()
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"dearrow-bot/pkg/i18n"
+	"log/slog"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/lmittmann/tint"
+)
+
+func (h *Handler) HandlePreferencesCurrent(event *handler.CommandEvent) error {
+	userID := event.User().ID
+	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
+	cfg, err := h.Bot.DB.GetUserConfig(event.Ctx, userID)
+	if err != nil {
+		slog.Error("dearrow: error while getting user preferences", slog.Any("user.id", userID), tint.Err(err))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.ErrorGetPreferences)))
+	}
+	if cfg.OptedOut {
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.PreferencesOptedOut)))
+	}
+	return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.PreferencesOptedIn)))
+}
+
+func (h *Handler) HandlePreferencesSet(data discord.SlashCommandInteractionData, event *handler.CommandEvent) error {
+	userID := event.User().ID
+	replies := data.Bool("replies")
+	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
+	if err := h.Bot.DB.UpdateUserOptedOut(event.Ctx, userID, !replies); err != nil {
+		slog.Error("dearrow: error while updating user preferences", slog.Bool("replies", replies), slog.Any("user.id", userID), tint.Err(err))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.ErrorUpdatePreferences)))
+	}
+	if replies {
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.PreferencesSetOptedIn)))
+	}
+	return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.PreferencesSetOptedOut)))
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"dearrow-bot/pkg/i18n"
+	"dearrow-bot/pkg/util"
+	"log/slog"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/lmittmann/tint"
+	"golang.org/x/sync/errgroup"
+)
+
+// ShowOriginalButtonID is the custom ID of the button attached to DeArrow replies.
+const ShowOriginalButtonID = "/show-original"
+
+// ShowOriginalButton returns the action row letting users see the original embeds of a reply, labelled in the locale.
+func ShowOriginalButton(locale discord.Locale) discord.LayoutComponent {
+	return discord.NewActionRow(discord.NewSecondaryButton(i18n.T(locale, i18n.ShowOriginalButton), ShowOriginalButtonID))
+}
+
+// HandleShowOriginal responds with the original embeds of the videos in the reply. They're fetched in parallel to
+// respond in time.
+func (h *Handler) HandleShowOriginal(data discord.ButtonInteractionData, event *handler.ComponentEvent) error {
+	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
+	var videoIDs []string
+	for _, embed := range event.Message.Embeds {
+		if videoID := util.ParseVideoURL(embed.URL); videoID != "" {
+			videoIDs = append(videoIDs, videoID)
+		}
+	}
+	if len(videoIDs) == 0 {
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.ShowOriginalUnavailable)))
+	}
+
+	embeds := make([]discord.Embed, len(videoIDs))
+	eg, ctx := errgroup.WithContext(event.Ctx)
+	for i, videoID := range videoIDs {
+		eg.Go(func() error {
+			original, err := h.Bot.Client.FetchEmbed(ctx, videoID)
+			if err != nil {
+				slog.Error("dearrow: error while fetching original embed", slog.String("video.id", videoID), tint.Err(err))
+				return err
+			}
+			embeds[i] = *original
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.ErrorFetchOriginal)))
+	}
+	return event.CreateMessage(messageCreate.WithEmbeds(embeds...))
+}
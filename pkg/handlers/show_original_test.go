@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"dearrow-bot/pkg"
+	"dearrow-bot/pkg/dearrow"
+	"dearrow-bot/pkg/dearrow/dearrowtest"
+	"dearrow-bot/pkg/i18n"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/rest"
+)
+
+const (
+	videoID      = "dQw4w9WgXcQ"
+	otherVideoID = "9bZkp7q5f0w"
+)
+
+// redirectTransport sends all requests to the server, e.g. oEmbed requests meant for YouTube.
+type redirectTransport struct {
+	server *url.URL
+}
+
+func (t redirectTransport) RoundTrip(rq *http.Request) (*http.Response, error) {
+	rq = rq.Clone(rq.Context())
+	rq.URL.Scheme, rq.URL.Host = t.server.Scheme, t.server.Host
+	return http.DefaultTransport.RoundTrip(rq)
+}
+
+// interactionResponse is a response sent by a handler.
+type interactionResponse struct {
+	responseType discord.InteractionResponseType
+	message      discord.MessageCreate
+}
+
+func newTestHandler(t *testing.T, server *dearrowtest.Server) *Handler {
+	t.Helper()
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpClient := &http.Client{Timeout: time.Second, Transport: redirectTransport{server: serverURL}}
+	client, err := dearrow.New(httpClient, httpClient,
+		dearrow.WithAPIURLs(server.URL, server.URL),
+		dearrow.WithThumbnailCache(0, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewHandler(t.Context(), &pkg.Bot{Client: client}, &pkg.Config{})
+}
+
+// clickShowOriginal clicks the show original button of a reply with the embeds of the videos. The respond function
+// can fail responses.
+func clickShowOriginal(t *testing.T, h *Handler, videoIDs []string, respond func(int) error) []interactionResponse {
+	t.Helper()
+	embeds := make([]string, 0, len(videoIDs))
+	for _, id := range videoIDs {
+		embeds = append(embeds, fmt.Sprintf(`{"url":"https://www.youtube.com/watch?v=%s"}`, id))
+	}
+	interaction, err := discord.UnmarshalInteraction(fmt.Appendf(nil, `{
+		"id": "1", "application_id": "2", "type": 3, "token": "token", "version": 1, "locale": "en-US", "channel_id": "3",
+		"user": {"id": "4", "username": "user"},
+		"data": {"component_type": 2, "custom_id": %q},
+		"message": {"id": "5", "channel_id": "3", "author": {"id": "6", "username": "dearrow"}, "timestamp": "2026-01-01T00:00:00Z", "embeds": [%s]}
+	}`, ShowOriginalButtonID, strings.Join(embeds, ",")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var responses []interactionResponse
+	h.OnEvent(&events.InteractionCreate{
+		GenericEvent: events.NewGenericEvent(nil, 0, 0),
+		Interaction:  interaction,
+		Respond: func(responseType discord.InteractionResponseType, data discord.InteractionResponseData, _ ...rest.RequestOpt) error {
+			message, _ := data.(discord.MessageCreate)
+			responses = append(responses, interactionResponse{responseType: responseType, message: message})
+			if respond != nil {
+				return respond(len(responses))
+			}
+			return nil
+		},
+	})
+	return responses
+}
+
+func oEmbed(title string) dearrowtest.Response {
+	return dearrowtest.JSON(map[string]any{"title": title, "author_name": "Author", "author_url": "https://www.youtube.com/@author"})
+}
+
+func TestHandleShowOriginal(t *testing.T) {
+	server := dearrowtest.NewServer()
+	t.Cleanup(server.Close)
+	server.SetOEmbed(videoID, oEmbed("Original"))
+	server.SetOEmbed(otherVideoID, oEmbed("Other original"))
+
+	responses := clickShowOriginal(t, newTestHandler(t, server), []string{videoID, otherVideoID}, nil)
+	if len(responses) != 1 {
+		t.Fatalf("expected a single response, got %d", len(responses))
+	}
+	message := responses[0].message
+	if message.Flags != discord.MessageFlagEphemeral {
+		t.Errorf("expected an ephemeral response, got flags %d", message.Flags)
+	}
+	if len(message.Embeds) != 2 || message.Embeds[0].Title != "Original" || message.Embeds[1].Title != "Other original" {
+		t.Errorf("expected the original embeds in order, got %+v", message.Embeds)
+	}
+}
+
+func TestHandleShowOriginalFailure(t *testing.T) {
+	server := dearrowtest.NewServer()
+	t.Cleanup(server.Close)
+	server.SetOEmbed(videoID, oEmbed("Original"))
+	server.SetOEmbed(otherVideoID, dearrowtest.Status(http.StatusInternalServerError))
+
+	responses := clickShowOriginal(t, newTestHandler(t, server), []string{videoID, otherVideoID}, nil)
+	if len(responses) != 1 {
+		t.Fatalf("expected a single response, got %d", len(responses))
+	}
+	if expected := i18n.T(discord.LocaleEnglishUS, i18n.ErrorFetchOriginal); responses[0].message.Content != expected {
+		t.Errorf("expected %q, got %q", expected, responses[0].message.Content)
+	}
+}
+
+func TestComponentError(t *testing.T) {
+	server := dearrowtest.NewServer()
+	t.Cleanup(server.Close)
+	server.SetOEmbed(videoID, oEmbed("Original"))
+	err := errors.New("unknown interaction")
+
+	responses := clickShowOriginal(t, newTestHandler(t, server), []string{videoID}, func(n int) error {
+		if n == 1 {
+			return err
+		}
+		return nil
+	})
+	if len(responses) != 2 {
+		t.Fatalf("expected the error to be reported in a second response, got %d responses", len(responses))
+	}
+	if expected := i18n.T(discord.LocaleEnglishUS, i18n.ErrorComponent, err); responses[1].message.Content != expected {
+		t.Errorf("expected %q, got %q", expected, responses[1].message.Content)
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"dearrow-bot/pkg/config"
+	"dearrow-bot/pkg/i18n"
 	"log/slog"
 
 	"github.com/disgoorg/disgo/discord"
@@ -10,17 +11,17 @@ import (
 )
 
 func (h *Handler) HandleThumbnailModeCurrent(event *handler.CommandEvent) error {
-	return h.modeCurrentHandler(event, func(cfg config.Guild) string {
-		return cfg.ThumbnailMode.String()
+	return h.modeCurrentHandler(event, func(cfg config.Guild) any {
+		return cfg.ThumbnailMode
 	})
 }
 
 func (h *Handler) HandleThumbnailModeSet(data discord.SlashCommandInteractionData, event *handler.CommandEvent) error {
 	thumbnailMode := config.ThumbnailMode(data.Int("mode"))
 	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
-	if err := h.Bot.DB.UpdateGuildThumbnailMode(*event.GuildID(), thumbnailMode); err != nil {
+	if err := h.Bot.DB.UpdateGuildThumbnailMode(event.Ctx, *event.GuildID(), thumbnailMode); err != nil {
 		slog.Error("dearrow: error while updating thumbnail mode", slog.Any("mode", thumbnailMode), slog.Any("guild.id", *event.GuildID()), tint.Err(err))
-		return event.CreateMessage(messageCreate.WithContent("There was an error while updating the thumbnail mode."))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.ErrorUpdateThumbnailMode)))
 	}
-	return event.CreateMessage(messageCreate.WithContentf("Mode has been set to **%s**.", thumbnailMode))
+	return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.ModeSet, i18n.Enum(event.Locale(), thumbnailMode))))
 }
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"dearrow-bot/pkg/config"
+	"dearrow-bot/pkg/i18n"
+	"log/slog"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/lmittmann/tint"
+)
+
+func (h *Handler) HandleTitleFormatCurrent(event *handler.CommandEvent) error {
+	return h.modeCurrentHandler(event, func(cfg config.Guild) any {
+		return cfg.TitleFormat
+	})
+}
+
+func (h *Handler) HandleTitleFormatSet(data discord.SlashCommandInteractionData, event *handler.CommandEvent) error {
+	titleFormat := config.TitleFormat(data.Int("mode"))
+	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
+	if err := h.Bot.DB.UpdateGuildTitleFormat(event.Ctx, *event.GuildID(), titleFormat); err != nil {
+		slog.Error("dearrow: error while updating title format", slog.Any("mode", titleFormat), slog.Any("guild.id", *event.GuildID()), tint.Err(err))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.ErrorUpdateTitleFormat)))
+	}
+	return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.ModeSet, i18n.Enum(event.Locale(), titleFormat))))
+}
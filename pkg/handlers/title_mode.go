@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"dearrow-bot/pkg/config"
+	"dearrow-bot/pkg/i18n"
 	"log/slog"
 
 	"github.com/disgoorg/disgo/discord"
@@ -10,17 +11,17 @@ import (
 )
 
 func (h *Handler) HandleOriginalTitleModeCurrent(event *handler.CommandEvent) error {
-	return h.modeCurrentHandler(event, func(cfg config.Guild) string {
-		return cfg.OriginalTitleMode.String()
+	return h.modeCurrentHandler(event, func(cfg config.Guild) any {
+		return cfg.OriginalTitleMode
 	})
 }
 
 func (h *Handler) HandleOriginalTitleModeSet(data discord.SlashCommandInteractionData, event *handler.CommandEvent) error {
 	originalTitleMode := config.OriginalTitleMode(data.Int("mode"))
 	messageCreate := discord.NewMessageCreate().WithEphemeral(true)
-	if err := h.Bot.DB.UpdateGuildTitleMode(*event.GuildID(), originalTitleMode); err != nil {
+	if err := h.Bot.DB.UpdateGuildTitleMode(event.Ctx, *event.GuildID(), originalTitleMode); err != nil {
 		slog.Error("dearrow: error while updating title mode", slog.Any("mode", originalTitleMode), slog.Any("guild.id", *event.GuildID()), tint.Err(err))
-		return event.CreateMessage(messageCreate.WithContent("There was an error while updating the title mode."))
+		return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.ErrorUpdateTitleMode)))
 	}
-	return event.CreateMessage(messageCreate.WithContentf("Mode has been set to **%s**.", originalTitleMode))
+	return event.CreateMessage(messageCreate.WithContent(i18n.T(event.Locale(), i18n.ModeSet, i18n.Enum(event.Locale(), originalTitleMode))))
 }
@@ -0,0 +1,179 @@
+package i18n
+
+import "github.com/disgoorg/disgo/discord"
+
+// CommandName returns the name localizations of the command, subcommand or option at the path, which is written like
+// the routes of the handler, e.g. "/configure/thumbnails/set/mode".
+func CommandName(path string) map[discord.Locale]string {
+	return Localizations(commandKey(path, "name"))
+}
+
+// CommandDescription returns the description of the command, subcommand or option at the path in DefaultLocale and
+// its localizations.
+func CommandDescription(path string) (string, map[discord.Locale]string) {
+	key := commandKey(path, "description")
+	return T(DefaultLocale, key), Localizations(key)
+}
+
+func commandKey(path string, field string) Key {
+	return Key("command" + path + "." + field)
+}
+
+var englishCommands = map[Key]string{
+	"command/configure.description":                                 "Configure DeArrow for this server",
+	"command/configure/thumbnails.description":                      "Configure how thumbnails are replaced",
+	"command/configure/thumbnails/current.description":              "Show the current thumbnail mode",
+	"command/configure/thumbnails/set.description":                  "Set the thumbnail mode",
+	"command/configure/thumbnails/set/mode.description":             "The thumbnail mode for videos without thumbnail submissions",
+	"command/configure/titles.description":                          "Configure how original titles are shown",
+	"command/configure/titles/current.description":                  "Show the current title mode",
+	"command/configure/titles/set.description":                      "Set the title mode",
+	"command/configure/titles/set/mode.description":                 "Whether original titles are shown below replaced ones",
+	"command/configure/formatting.description":                      "Configure how titles are formatted",
+	"command/configure/formatting/current.description":              "Show the current title format",
+	"command/configure/formatting/set.description":                  "Set the title format",
+	"command/configure/formatting/set/mode.description":             "The format of replaced and original titles",
+	"command/configure/language.description":                        "Configure the language of submissions",
+	"command/configure/language/current.description":                "Show the current language",
+	"command/configure/language/set.description":                    "Set the language",
+	"command/configure/language/set/language.description":           "A language tag like de or pt-BR, or default",
+	"command/configure/links.description":                           "Configure which links are replaced",
+	"command/configure/links/current.description":                   "Show the current link mode",
+	"command/configure/links/set.description":                       "Set the link mode",
+	"command/configure/links/set/mode.description":                  "Which YouTube links are replaced",
+	"command/configure/policy.description":                          "Configure which submissions replace originals",
+	"command/configure/policy/current.description":                  "Show the current policies",
+	"command/configure/policy/set.description":                      "Set a policy",
+	"command/configure/policy/set/target.description":               "Whether the policy applies to titles or thumbnails",
+	"command/configure/policy/set/min-votes.description":            "Submissions with fewer votes are ignored",
+	"command/configure/policy/set/locked-only.description":          "Only use submissions locked by a VIP",
+	"command/configure/policy/set/require-non-original.description": "Never use submissions voting for the original",
+	"command/configure/casual.description":                          "Configure casual mode",
+	"command/configure/casual/current.description":                  "Show the current casual mode settings",
+	"command/configure/casual/set.description":                      "Set casual mode settings",
+	"command/configure/casual/set/enabled.description":              "Whether originals are kept for videos voted casual",
+	"command/configure/casual/set/funny.description":                "Votes needed in the funny category, 0 to ignore it",
+	"command/configure/casual/set/clever.description":               "Votes needed in the clever category, 0 to ignore it",
+	"command/configure/casual/set/descriptive.description":          "Votes needed in the descriptive category, 0 to ignore it",
+	"command/configure/casual/set/other.description":                "Votes needed in the other category, 0 to ignore it",
+	"command/configure/status.description":                          "Turn DeArrow on or off in this server",
+	"command/configure/status/current.description":                  "Show whether DeArrow is enabled and the allow- and denylists",
+	"command/configure/status/set.description":                      "Enable or disable DeArrow",
+	"command/configure/status/set/enabled.description":              "Whether DeArrow replies in this server",
+	"command/configure/allowlist.description":                       "Only reply in these channels or to these roles",
+	"command/configure/allowlist/add.description":                   "Add a channel or role to the allowlist",
+	"command/configure/allowlist/add/channel.description":           "The channel or category to add",
+	"command/configure/allowlist/add/role.description":              "The role to add",
+	"command/configure/allowlist/remove.description":                "Remove a channel or role from the allowlist",
+	"command/configure/allowlist/remove/channel.description":        "The channel or category to remove",
+	"command/configure/allowlist/remove/role.description":           "The role to remove",
+	"command/configure/denylist.description":                        "Never reply in these channels or to these roles",
+	"command/configure/denylist/add.description":                    "Add a channel or role to the denylist",
+	"command/configure/denylist/add/channel.description":            "The channel or category to add",
+	"command/configure/denylist/add/role.description":               "The role to add",
+	"command/configure/denylist/remove.description":                 "Remove a channel or role from the denylist",
+	"command/configure/denylist/remove/channel.description":         "The channel or category to remove",
+	"command/configure/denylist/remove/role.description":            "The role to remove",
+	"command/configure/channel.description":                         "Override the configuration of a channel",
+	"command/configure/channel/set.description":                     "Set overrides of a channel",
+	"command/configure/channel/set/channel.description":             "The channel, category or thread, defaults to the current one",
+	"command/configure/channel/set/enabled.description":             "Whether DeArrow replies in the channel",
+	"command/configure/channel/set/thumbnails.description":          "The thumbnail mode in the channel",
+	"command/configure/channel/set/titles.description":              "The title mode in the channel",
+	"command/configure/channel/set/language.description":            "The language in the channel, or default",
+	"command/configure/channel/view.description":                    "Show the configuration of a channel",
+	"command/configure/channel/view/channel.description":            "The channel, category or thread, defaults to the current one",
+	"command/configure/channel/clear.description":                   "Remove all overrides of a channel",
+	"command/configure/channel/clear/channel.description":           "The channel, category or thread, defaults to the current one",
+	"command/branding.description":                                  "Show the DeArrow branding of a video",
+	"command/branding/video.description":                            "The video link or ID",
+	"command/branding/hide.description":                             "Whether only you can see the response, defaults to true",
+	"command/preferences.description":                               "Manage your DeArrow preferences",
+	"command/preferences/current.description":                       "Show your preferences",
+	"command/preferences/set.description":                           "Set your preferences",
+	"command/preferences/set/replies.description":                   "Whether DeArrow replies to your messages",
+}
+
+var germanCommands = map[Key]string{
+	"command/configure.name":                                        "konfigurieren",
+	"command/configure.description":                                 "DeArrow für diesen Server konfigurieren",
+	"command/configure/thumbnails.description":                      "Festlegen, wie Thumbnails ersetzt werden",
+	"command/configure/thumbnails/current.description":              "Aktuellen Thumbnail-Modus anzeigen",
+	"command/configure/thumbnails/set.description":                  "Thumbnail-Modus festlegen",
+	"command/configure/thumbnails/set/mode.description":             "Der Thumbnail-Modus für Videos ohne eingereichte Thumbnails",
+	"command/configure/titles.name":                                 "titel",
+	"command/configure/titles.description":                          "Festlegen, wie Originaltitel angezeigt werden",
+	"command/configure/titles/current.description":                  "Aktuellen Titel-Modus anzeigen",
+	"command/configure/titles/set.description":                      "Titel-Modus festlegen",
+	"command/configure/titles/set/mode.description":                 "Ob Originaltitel unter ersetzten Titeln angezeigt werden",
+	"command/configure/formatting.name":                             "formatierung",
+	"command/configure/formatting.description":                      "Festlegen, wie Titel formatiert werden",
+	"command/configure/formatting/current.description":              "Aktuelle Titelformatierung anzeigen",
+	"command/configure/formatting/set.description":                  "Titelformatierung festlegen",
+	"command/configure/formatting/set/mode.description":             "Die Formatierung ersetzter und originaler Titel",
+	"command/configure/language.name":                               "sprache",
+	"command/configure/language.description":                        "Sprache der Einreichungen festlegen",
+	"command/configure/language/current.description":                "Aktuelle Sprache anzeigen",
+	"command/configure/language/set.description":                    "Sprache festlegen",
+	"command/configure/language/set/language.description":           "Ein Sprachkürzel wie de oder pt-BR, oder default",
+	"command/configure/links.description":                           "Festlegen, welche Links ersetzt werden",
+	"command/configure/links/current.description":                   "Aktuellen Link-Modus anzeigen",
+	"command/configure/links/set.description":                       "Link-Modus festlegen",
+	"command/configure/links/set/mode.description":                  "Welche YouTube-Links ersetzt werden",
+	"command/configure/policy.name":                                 "richtlinie",
+	"command/configure/policy.description":                          "Festlegen, welche Einreichungen Originale ersetzen",
+	"command/configure/policy/current.description":                  "Aktuelle Richtlinien anzeigen",
+	"command/configure/policy/set.description":                      "Richtlinie festlegen",
+	"command/configure/policy/set/target.description":               "Ob die Richtlinie für Titel oder Thumbnails gilt",
+	"command/configure/policy/set/min-votes.description":            "Einreichungen mit weniger Stimmen werden ignoriert",
+	"command/configure/policy/set/locked-only.description":          "Nur von VIPs gesperrte Einreichungen verwenden",
+	"command/configure/policy/set/require-non-original.description": "Niemals Einreichungen verwenden, die für das Original stimmen",
+	"command/configure/casual.description":                          "Casual-Modus konfigurieren",
+	"command/configure/casual/current.description":                  "Aktuelle Einstellungen des Casual-Modus anzeigen",
+	"command/configure/casual/set.description":                      "Einstellungen des Casual-Modus festlegen",
+	"command/configure/casual/set/enabled.description":              "Ob Originale bei als casual bewerteten Videos erhalten bleiben",
+	"command/configure/casual/set/funny.description":                "Benötigte Stimmen in der Kategorie lustig, 0 zum Ignorieren",
+	"command/configure/casual/set/clever.description":               "Benötigte Stimmen in der Kategorie clever, 0 zum Ignorieren",
+	"command/configure/casual/set/descriptive.description":          "Benötigte Stimmen in der Kategorie beschreibend, 0 zum Ignorieren",
+	"command/configure/casual/set/other.description":                "Benötigte Stimmen in der Kategorie sonstiges, 0 zum Ignorieren",
+	"command/configure/status.description":                          "DeArrow auf diesem Server ein- oder ausschalten",
+	"command/configure/status/current.description":                  "Anzeigen, ob DeArrow aktiviert ist, sowie die Allow- und Denylists",
+	"command/configure/status/set.description":                      "DeArrow aktivieren oder deaktivieren",
+	"command/configure/status/set/enabled.description":              "Ob DeArrow auf diesem Server antwortet",
+	"command/configure/allowlist.description":                       "Nur in diesen Kanälen oder auf diese Rollen antworten",
+	"command/configure/allowlist/add.description":                   "Einen Kanal oder eine Rolle zur Allowlist hinzufügen",
+	"command/configure/allowlist/add/channel.description":           "Der Kanal oder die Kategorie",
+	"command/configure/allowlist/add/role.description":              "Die Rolle",
+	"command/configure/allowlist/remove.description":                "Einen Kanal oder eine Rolle von der Allowlist entfernen",
+	"command/configure/allowlist/remove/channel.description":        "Der Kanal oder die Kategorie",
+	"command/configure/allowlist/remove/role.description":           "Die Rolle",
+	"command/configure/denylist.description":                        "Nie in diesen Kanälen oder auf diese Rollen antworten",
+	"command/configure/denylist/add.description":                    "Einen Kanal oder eine Rolle zur Denylist hinzufügen",
+	"command/configure/denylist/add/channel.description":            "Der Kanal oder die Kategorie",
+	"command/configure/denylist/add/role.description":               "Die Rolle",
+	"command/configure/denylist/remove.description":                 "Einen Kanal oder eine Rolle von der Denylist entfernen",
+	"command/configure/denylist/remove/channel.description":         "Der Kanal oder die Kategorie",
+	"command/configure/denylist/remove/role.description":            "Die Rolle",
+	"command/configure/channel.name":                                "kanal",
+	"command/configure/channel.description":                         "Die Konfiguration eines Kanals überschreiben",
+	"command/configure/channel/set.description":                     "Einstellungen eines Kanals überschreiben",
+	"command/configure/channel/set/channel.description":             "Der Kanal, die Kategorie oder der Thread, standardmäßig der aktuelle",
+	"command/configure/channel/set/enabled.description":             "Ob DeArrow in dem Kanal antwortet",
+	"command/configure/channel/set/thumbnails.description":          "Der Thumbnail-Modus in dem Kanal",
+	"command/configure/channel/set/titles.description":              "Der Titel-Modus in dem Kanal",
+	"command/configure/channel/set/language.description":            "Die Sprache in dem Kanal, oder default",
+	"command/configure/channel/view.description":                    "Die Konfiguration eines Kanals anzeigen",
+	"command/configure/channel/view/channel.description":            "Der Kanal, die Kategorie oder der Thread, standardmäßig der aktuelle",
+	"command/configure/channel/clear.description":                   "Alle Einstellungen eines Kanals zurücksetzen",
+	"command/configure/channel/clear/channel.description":           "Der Kanal, die Kategorie oder der Thread, standardmäßig der aktuelle",
+	"command/branding.description":                                  "Das DeArrow-Branding eines Videos anzeigen",
+	"command/branding/video.description":                            "Der Link oder die ID des Videos",
+	"command/branding/hide.description":                             "Ob nur du die Antwort sehen kannst, standardmäßig ja",
+	"command/preferences.name":                                      "einstellungen",
+	"command/preferences.description":                               "Deine DeArrow-Einstellungen verwalten",
+	"command/preferences/current.description":                       "Deine Einstellungen anzeigen",
+	"command/preferences/set.description":                           "Deine Einstellungen festlegen",
+	"command/preferences/set/replies.description":                   "Ob DeArrow auf deine Nachrichten antwortet",
+	"command/Fetch branding.name":                                   "Branding abrufen",
+	"command/Delete embeds.name":                                    "Embeds löschen",
+}
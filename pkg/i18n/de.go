@@ -0,0 +1,111 @@
+package i18n
+
+var german = map[Key]string{
+	ErrorCommand:             "Beim Ausführen des Befehls ist ein Fehler aufgetreten: %v",
+	ErrorComponent:           "Beim Verarbeiten der Schaltfläche ist ein Fehler aufgetreten: %v",
+	ErrorGetGuildConfig:      "Beim Laden der Serverkonfiguration ist ein Fehler aufgetreten.",
+	ErrorGetChannelConfig:    "Beim Laden der Kanalkonfiguration ist ein Fehler aufgetreten.",
+	ErrorUpdateChannelConfig: "Beim Aktualisieren der Kanalkonfiguration ist ein Fehler aufgetreten.",
+	ErrorClearChannelConfig:  "Beim Zurücksetzen der Kanalkonfiguration ist ein Fehler aufgetreten.",
+	ErrorUpdateThumbnailMode: "Beim Aktualisieren des Thumbnail-Modus ist ein Fehler aufgetreten.",
+	ErrorUpdateTitleMode:     "Beim Aktualisieren des Titel-Modus ist ein Fehler aufgetreten.",
+	ErrorUpdateLinkMode:      "Beim Aktualisieren des Link-Modus ist ein Fehler aufgetreten.",
+	ErrorUpdateTitleFormat:   "Beim Aktualisieren der Titelformatierung ist ein Fehler aufgetreten.",
+	ErrorUpdateLanguage:      "Beim Aktualisieren der Sprache ist ein Fehler aufgetreten.",
+	ErrorUpdateCasualMode:    "Beim Aktualisieren des Casual-Modus ist ein Fehler aufgetreten.",
+	ErrorUpdatePolicy:        "Beim Aktualisieren der Richtlinie ist ein Fehler aufgetreten.",
+	ErrorUpdateStatus:        "Beim Aktualisieren des Status ist ein Fehler aufgetreten.",
+	ErrorUpdateList:          "Beim Aktualisieren der %s ist ein Fehler aufgetreten.",
+	ErrorGetPreferences:      "Beim Laden deiner Einstellungen ist ein Fehler aufgetreten.",
+	ErrorUpdatePreferences:   "Beim Aktualisieren deiner Einstellungen ist ein Fehler aufgetreten.",
+	ErrorFetchOriginal:       "Beim Laden des Original-Embeds ist ein Fehler aufgetreten.",
+
+	ModeCurrent: "Aktueller Modus: **%s**.",
+	ModeSet:     "Modus wurde auf **%s** gesetzt.",
+
+	StateEnabled:  "Aktiviert",
+	StateDisabled: "Deaktiviert",
+	Yes:           "Ja",
+	No:            "Nein",
+
+	LabelThumbnails: "Thumbnails",
+	LabelTitles:     "Titel",
+	LabelLanguage:   "Sprache",
+
+	BrandingInvalidInput: "Aus der Eingabe konnte keine Video-ID gelesen werden.",
+	BrandingTimeout:      "Die DeArrow-API hat nicht innerhalb von 2 Sekunden geantwortet.",
+	BrandingUnavailable:  "Die DeArrow-API ist derzeit nicht erreichbar, versuche es später erneut.",
+	BrandingStatus:       "Die DeArrow-API hat einen Fehlercode zurückgegeben: **%d**",
+	BrandingTooLong:      "Die Antwort ist länger als **%d** Zeichen (**%d**). Die vollständige Antwort findest du [hier](%s).",
+
+	DeleteNotReply:        "Die Nachricht ist keine Antwort.",
+	DeleteNotDeArrowReply: "Die Nachricht ist keine Antwort von DeArrow.",
+	DeleteFetchFailed:     "Die ursprüngliche Nachricht konnte nicht geladen werden.",
+	DeleteNotAuthor:       "Nur der Autor der Nachricht kann die DeArrow-Embeds löschen.",
+	DeleteDeleting:        "DeArrow-Embeds werden gelöscht.",
+
+	ChannelNoSettings:     "Gib mindestens eine Einstellung zum Überschreiben an.",
+	ChannelUpdated:        "Die Einstellungen für %s wurden aktualisiert.",
+	ChannelNoOverrides:    "%s hat keine eigenen Einstellungen.",
+	ChannelCleared:        "Die Einstellungen für %s wurden zurückgesetzt.",
+	ChannelHeader:         "Konfiguration von %s:",
+	ChannelSourceHere:     "hier gesetzt",
+	ChannelSourceInherits: "geerbt von %s",
+	ChannelSourceGuild:    "Serverstandard",
+
+	StatusCurrent:     "DeArrow ist auf diesem Server **%s**.",
+	StatusEnabled:     "DeArrow wurde auf diesem Server **aktiviert**.",
+	StatusDisabled:    "DeArrow wurde auf diesem Server **deaktiviert**.",
+	ListEmpty:         "*leer*",
+	ListMissingEntry:  "Gib einen Kanal oder eine Rolle an.",
+	ListAdded:         "%s wurde zur %s hinzugefügt.",
+	ListRemoved:       "%s wurde aus der %s entfernt.",
+	ListChannelAllow:  "Kanal-Allowlist",
+	ListChannelDeny:   "Kanal-Denylist",
+	ListRoleAllow:     "Rollen-Allowlist",
+	ListRoleDeny:      "Rollen-Denylist",
+	LanguageCurrent:   "Aktuelle Sprache: **%s**.",
+	LanguageSet:       "Die Sprache wurde auf **%s** gesetzt. Für Videos ohne Titel in dieser Sprache werden die Standard-Einreichungen verwendet.",
+	LanguageInvalid:   "Gib ein Sprachkürzel wie `de` oder `pt-BR` an, oder `default`.",
+	LanguageDefault:   "Standard-Einreichungen",
+	CasualHeader:      "Der Casual-Modus ist **%s**. Originaltitel und -thumbnails bleiben erhalten, wenn genug Casual-Stimmen sie so einstufen:",
+	CasualIgnored:     "*ignoriert*",
+	CasualVotes:       "**%d** Stimme(n)",
+	CasualFunny:       "Lustig",
+	CasualClever:      "Clever",
+	CasualDescriptive: "Beschreibend",
+	CasualOther:       "Sonstiges",
+
+	PolicyMinVotes:           "Mindestanzahl an Stimmen",
+	PolicyLockedOnly:         "Nur gesperrte",
+	PolicyRequireNonOriginal: "Original ausschließen",
+
+	PreferencesOptedOut:    "DeArrow **antwortet nicht** auf deine Nachrichten.",
+	PreferencesOptedIn:     "DeArrow **antwortet** auf deine Nachrichten.",
+	PreferencesSetOptedOut: "DeArrow antwortet ab sofort nicht mehr auf deine Nachrichten.",
+	PreferencesSetOptedIn:  "DeArrow antwortet ab sofort auf deine Nachrichten.",
+
+	ShowOriginalButton:      "Original anzeigen",
+	ShowOriginalUnavailable: "Das Original-Embed ist nicht mehr verfügbar.",
+
+	EmbedOriginalTitle: "Originaltitel: %s",
+	EmbedFooterTip:     `Tipp: Nutze Apps -> "Delete embeds", um die DeArrow-Nachricht zu löschen.`,
+	EmbedSpoiler:       "Spoiler",
+
+	ThumbnailModeRandomTime: "Screenshot von einem zufälligen Zeitpunkt anzeigen",
+	ThumbnailModeBlank:      "Kein Thumbnail anzeigen",
+	ThumbnailModeOriginal:   "Original-Thumbnail anzeigen",
+
+	OriginalTitleModeShown:  "Originaltitel anzeigen",
+	OriginalTitleModeHidden: "Originaltitel ausblenden",
+
+	LinkModeEmbeds:  "Eingebettete YouTube-Links ersetzen",
+	LinkModeContent: "Alle YouTube-Links ersetzen, auch unterdrückte und gespoilerte",
+
+	TitleFormatNone:                 "Titel unverändert lassen",
+	TitleFormatCapitalizeWords:      "Jedes Wort Großschreiben",
+	TitleFormatTitleCase:            "Title Case",
+	TitleFormatSentenceCase:         "Satzschreibung",
+	TitleFormatLowerCase:            "kleinschreibung",
+	TitleFormatFirstLetterUppercase: "Erster Buchstabe groß",
+}
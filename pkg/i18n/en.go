@@ -0,0 +1,111 @@
+package i18n
+
+var english = map[Key]string{
+	ErrorCommand:             "There was an error while handling the command: %v",
+	ErrorComponent:           "There was an error while handling the button: %v",
+	ErrorGetGuildConfig:      "There was an error while getting the guild configuration.",
+	ErrorGetChannelConfig:    "There was an error while getting the channel configuration.",
+	ErrorUpdateChannelConfig: "There was an error while updating the channel configuration.",
+	ErrorClearChannelConfig:  "There was an error while clearing the channel configuration.",
+	ErrorUpdateThumbnailMode: "There was an error while updating the thumbnail mode.",
+	ErrorUpdateTitleMode:     "There was an error while updating the title mode.",
+	ErrorUpdateLinkMode:      "There was an error while updating the link mode.",
+	ErrorUpdateTitleFormat:   "There was an error while updating the title format.",
+	ErrorUpdateLanguage:      "There was an error while updating the language.",
+	ErrorUpdateCasualMode:    "There was an error while updating casual mode.",
+	ErrorUpdatePolicy:        "There was an error while updating the policy.",
+	ErrorUpdateStatus:        "There was an error while updating the status.",
+	ErrorUpdateList:          "There was an error while updating the %s.",
+	ErrorGetPreferences:      "There was an error while getting your preferences.",
+	ErrorUpdatePreferences:   "There was an error while updating your preferences.",
+	ErrorFetchOriginal:       "There was an error while fetching the original embed.",
+
+	ModeCurrent: "Current mode is set to **%s**.",
+	ModeSet:     "Mode has been set to **%s**.",
+
+	StateEnabled:  "Enabled",
+	StateDisabled: "Disabled",
+	Yes:           "Yes",
+	No:            "No",
+
+	LabelThumbnails: "Thumbnails",
+	LabelTitles:     "Titles",
+	LabelLanguage:   "Language",
+
+	BrandingInvalidInput: "Cannot extract video ID from input.",
+	BrandingTimeout:      "DeArrow API failed to respond within 2 seconds.",
+	BrandingUnavailable:  "DeArrow API is currently unavailable, try again later.",
+	BrandingStatus:       "DeArrow API returned a non-OK code: **%d**",
+	BrandingTooLong:      "Response is longer than **%d** chars (**%d**). See the full response [here](%s).",
+
+	DeleteNotReply:        "Message is not a reply.",
+	DeleteNotDeArrowReply: "Message is not a DeArrow reply.",
+	DeleteFetchFailed:     "Failed to fetch the parent message.",
+	DeleteNotAuthor:       "Only the message author can delete DeArrow embeds.",
+	DeleteDeleting:        "Deleting DeArrow embeds.",
+
+	ChannelNoSettings:     "Provide at least one setting to override.",
+	ChannelUpdated:        "Overrides for %s have been updated.",
+	ChannelNoOverrides:    "%s has no overrides.",
+	ChannelCleared:        "Overrides for %s have been cleared.",
+	ChannelHeader:         "Configuration of %s:",
+	ChannelSourceHere:     "set here",
+	ChannelSourceInherits: "inherited from %s",
+	ChannelSourceGuild:    "guild default",
+
+	StatusCurrent:     "DeArrow is **%s** in this server.",
+	StatusEnabled:     "DeArrow has been **enabled** in this server.",
+	StatusDisabled:    "DeArrow has been **disabled** in this server.",
+	ListEmpty:         "*empty*",
+	ListMissingEntry:  "Provide a channel or a role.",
+	ListAdded:         "%s has been added to the %s.",
+	ListRemoved:       "%s has been removed from the %s.",
+	ListChannelAllow:  "channel allowlist",
+	ListChannelDeny:   "channel denylist",
+	ListRoleAllow:     "role allowlist",
+	ListRoleDeny:      "role denylist",
+	LanguageCurrent:   "Current language is set to **%s**.",
+	LanguageSet:       "Language has been set to **%s**. Videos without titles in this language use the default submissions.",
+	LanguageInvalid:   "Provide a language tag like `de` or `pt-BR`, or `default`.",
+	LanguageDefault:   "Default submissions",
+	CasualHeader:      "Casual mode is **%s**. Original titles and thumbnails are kept if enough casual voters consider them:",
+	CasualIgnored:     "*ignored*",
+	CasualVotes:       "**%d** vote(s)",
+	CasualFunny:       "Funny",
+	CasualClever:      "Clever",
+	CasualDescriptive: "Descriptive",
+	CasualOther:       "Other",
+
+	PolicyMinVotes:           "Minimum votes",
+	PolicyLockedOnly:         "Locked only",
+	PolicyRequireNonOriginal: "Require non-original",
+
+	PreferencesOptedOut:    "DeArrow **doesn't reply** to your messages.",
+	PreferencesOptedIn:     "DeArrow **replies** to your messages.",
+	PreferencesSetOptedOut: "DeArrow will no longer reply to your messages.",
+	PreferencesSetOptedIn:  "DeArrow will now reply to your messages.",
+
+	ShowOriginalButton:      "Show original",
+	ShowOriginalUnavailable: "The original embed is no longer available.",
+
+	EmbedOriginalTitle: "Original title: %s",
+	EmbedFooterTip:     `Tip: Use Apps -> "Delete embeds" to delete the DeArrow message.`,
+	EmbedSpoiler:       "Spoiler",
+
+	ThumbnailModeRandomTime: "Show a screenshot from a random time",
+	ThumbnailModeBlank:      "Show no thumbnail",
+	ThumbnailModeOriginal:   "Show the original thumbnail",
+
+	OriginalTitleModeShown:  "Show original titles",
+	OriginalTitleModeHidden: "Hide original titles",
+
+	LinkModeEmbeds:  "Replace embedded YouTube links",
+	LinkModeContent: "Replace all YouTube links, including suppressed and spoilered ones",
+
+	TitleFormatNone:                 "Keep titles as they are",
+	TitleFormatCapitalizeWords:      "Capitalize Every Word",
+	TitleFormatTitleCase:            "Title Case",
+	TitleFormatSentenceCase:         "Sentence case",
+	TitleFormatLowerCase:            "lower case",
+	TitleFormatFirstLetterUppercase: "First letter uppercase",
+}
@@ -0,0 +1,57 @@
+package i18n
+
+import (
+	"dearrow-bot/pkg/config"
+	"fmt"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+var (
+	enumKeys = map[any]Key{
+		config.ThumbnailModeRandomTime: ThumbnailModeRandomTime,
+		config.ThumbnailModeBlank:      ThumbnailModeBlank,
+		config.ThumbnailModeOriginal:   ThumbnailModeOriginal,
+
+		config.OriginalTitleModeShown:  OriginalTitleModeShown,
+		config.OriginalTitleModeHidden: OriginalTitleModeHidden,
+
+		config.LinkModeEmbeds:  LinkModeEmbeds,
+		config.LinkModeContent: LinkModeContent,
+
+		config.TitleFormatNone:                 TitleFormatNone,
+		config.TitleFormatCapitalizeWords:      TitleFormatCapitalizeWords,
+		config.TitleFormatTitleCase:            TitleFormatTitleCase,
+		config.TitleFormatSentenceCase:         TitleFormatSentenceCase,
+		config.TitleFormatLowerCase:            TitleFormatLowerCase,
+		config.TitleFormatFirstLetterUppercase: TitleFormatFirstLetterUppercase,
+
+		config.ListChannelAllow: ListChannelAllow,
+		config.ListChannelDeny:  ListChannelDeny,
+		config.ListRoleAllow:    ListRoleAllow,
+		config.ListRoleDeny:     ListRoleDeny,
+
+		config.PolicyTargetTitles:     LabelTitles,
+		config.PolicyTargetThumbnails: LabelThumbnails,
+
+		config.CasualCategoryFunny:       CasualFunny,
+		config.CasualCategoryClever:      CasualClever,
+		config.CasualCategoryDescriptive: CasualDescriptive,
+		config.CasualCategoryOther:       CasualOther,
+	}
+)
+
+// EnumKey returns the key of the description of a configuration value, e.g. a config.ThumbnailMode, and false if
+// there's none.
+func EnumKey(v any) (Key, bool) {
+	key, ok := enumKeys[v]
+	return key, ok
+}
+
+// Enum returns the description of a configuration value in the locale, falling back to its fmt representation.
+func Enum(locale discord.Locale, v any) string {
+	if key, ok := enumKeys[v]; ok {
+		return T(locale, key)
+	}
+	return fmt.Sprint(v)
+}
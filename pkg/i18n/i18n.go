@@ -0,0 +1,71 @@
+// Package i18n holds the translations of all user facing strings of the bot.
+package i18n
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/disgoorg/disgo/cache"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// Key identifies a message in the catalogue.
+type Key string
+
+// DefaultLocale is used for locales without a catalogue and messages missing in a catalogue.
+const DefaultLocale = discord.LocaleEnglishUS
+
+var (
+	catalogues = map[discord.Locale]map[Key]string{
+		discord.LocaleEnglishUS: merge(english, englishCommands),
+		discord.LocaleEnglishGB: merge(english, englishCommands),
+		discord.LocaleGerman:    merge(german, germanCommands),
+	}
+)
+
+// T returns the message in the locale, formatted with the args like fmt.Sprintf. Messages missing in the locale
+// fall back to DefaultLocale.
+func T(locale discord.Locale, key Key, args ...any) string {
+	message, ok := catalogues[locale][key]
+	if !ok {
+		if message, ok = catalogues[DefaultLocale][key]; !ok {
+			message = string(key)
+		}
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Localizations returns the message in all locales other than DefaultLocale which translate it, e.g. for command
+// name and description localizations.
+func Localizations(key Key) map[discord.Locale]string {
+	localizations := make(map[discord.Locale]string)
+	for locale, catalogue := range catalogues {
+		if locale == DefaultLocale {
+			continue
+		}
+		if message, ok := catalogue[key]; ok {
+			localizations[locale] = message
+		}
+	}
+	return localizations
+}
+
+// GuildLocale returns the preferred locale of the guild, or DefaultLocale if the guild isn't cached.
+func GuildLocale(caches cache.Caches, guildID snowflake.ID) discord.Locale {
+	if guild, ok := caches.Guild(guildID); ok && guild.PreferredLocale != "" {
+		return discord.Locale(guild.PreferredLocale)
+	}
+	return DefaultLocale
+}
+
+func merge(catalogues ...map[Key]string) map[Key]string {
+	merged := make(map[Key]string)
+	for _, catalogue := range catalogues {
+		maps.Copy(merged, catalogue)
+	}
+	return merged
+}
@@ -0,0 +1,64 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+var verbRegex = regexp.MustCompile(`%[a-z]`)
+
+func TestCatalogues(t *testing.T) {
+	defaults := catalogues[DefaultLocale]
+	for locale, catalogue := range catalogues {
+		for key, message := range catalogue {
+			if strings.HasSuffix(string(key), ".name") { // command names are only localized
+				continue
+			}
+			defaultMessage, ok := defaults[key]
+			if !ok {
+				t.Errorf("%s: %q is missing in the default catalogue", locale, key)
+				continue
+			}
+			if verbs, defaultVerbs := verbRegex.FindAllString(message, -1), verbRegex.FindAllString(defaultMessage, -1); !slices.Equal(verbs, defaultVerbs) {
+				t.Errorf("%s: %q has verbs %v, expected %v", locale, key, verbs, defaultVerbs)
+			}
+		}
+	}
+	for v, key := range enumKeys {
+		if _, ok := defaults[key]; !ok {
+			t.Errorf("description %q of %v is missing in the default catalogue", key, v)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T(discord.LocaleGerman, ModeSet, "x"); got != "Modus wurde auf **x** gesetzt." {
+		t.Errorf("unexpected german message: %q", got)
+	}
+	if got := T(discord.LocaleFrench, ModeSet, "x"); got != "Mode has been set to **x**." {
+		t.Errorf("expected fallback to the default locale, got %q", got)
+	}
+	if got := T(discord.LocaleGerman, "missing"); got != "missing" {
+		t.Errorf("expected the key for missing messages, got %q", got)
+	}
+}
+
+func TestCommandLocalizations(t *testing.T) {
+	description, localizations := CommandDescription("/configure/thumbnails/set/mode")
+	if description != "The thumbnail mode for videos without thumbnail submissions" {
+		t.Errorf("unexpected description: %q", description)
+	}
+	if _, ok := localizations[discord.LocaleGerman]; !ok {
+		t.Errorf("expected a german description, got %v", localizations)
+	}
+	if _, ok := localizations[discord.LocaleEnglishGB]; !ok {
+		t.Errorf("expected an english description for other english locales, got %v", localizations)
+	}
+	if names := CommandName("/preferences"); names[discord.LocaleGerman] != "einstellungen" {
+		t.Errorf("unexpected name localizations: %v", names)
+	}
+}
@@ -0,0 +1,111 @@
+package i18n
+
+const (
+	ErrorCommand             Key = "error.command"
+	ErrorComponent           Key = "error.component"
+	ErrorGetGuildConfig      Key = "error.get_guild_config"
+	ErrorGetChannelConfig    Key = "error.get_channel_config"
+	ErrorUpdateChannelConfig Key = "error.update_channel_config"
+	ErrorClearChannelConfig  Key = "error.clear_channel_config"
+	ErrorUpdateThumbnailMode Key = "error.update_thumbnail_mode"
+	ErrorUpdateTitleMode     Key = "error.update_title_mode"
+	ErrorUpdateLinkMode      Key = "error.update_link_mode"
+	ErrorUpdateTitleFormat   Key = "error.update_title_format"
+	ErrorUpdateLanguage      Key = "error.update_language"
+	ErrorUpdateCasualMode    Key = "error.update_casual_mode"
+	ErrorUpdatePolicy        Key = "error.update_policy"
+	ErrorUpdateStatus        Key = "error.update_status"
+	ErrorUpdateList          Key = "error.update_list"
+	ErrorGetPreferences      Key = "error.get_preferences"
+	ErrorUpdatePreferences   Key = "error.update_preferences"
+	ErrorFetchOriginal       Key = "error.fetch_original"
+
+	ModeCurrent Key = "mode.current"
+	ModeSet     Key = "mode.set"
+
+	StateEnabled  Key = "state.enabled"
+	StateDisabled Key = "state.disabled"
+	Yes           Key = "yes"
+	No            Key = "no"
+
+	LabelThumbnails Key = "label.thumbnails"
+	LabelTitles     Key = "label.titles"
+	LabelLanguage   Key = "label.language"
+
+	BrandingInvalidInput Key = "branding.invalid_input"
+	BrandingTimeout      Key = "branding.timeout"
+	BrandingUnavailable  Key = "branding.unavailable"
+	BrandingStatus       Key = "branding.status"
+	BrandingTooLong      Key = "branding.too_long"
+
+	DeleteNotReply        Key = "delete.not_reply"
+	DeleteNotDeArrowReply Key = "delete.not_dearrow_reply"
+	DeleteFetchFailed     Key = "delete.fetch_failed"
+	DeleteNotAuthor       Key = "delete.not_author"
+	DeleteDeleting        Key = "delete.deleting"
+
+	ChannelNoSettings     Key = "channel.no_settings"
+	ChannelUpdated        Key = "channel.updated"
+	ChannelNoOverrides    Key = "channel.no_overrides"
+	ChannelCleared        Key = "channel.cleared"
+	ChannelHeader         Key = "channel.header"
+	ChannelSourceHere     Key = "channel.source_here"
+	ChannelSourceInherits Key = "channel.source_inherits"
+	ChannelSourceGuild    Key = "channel.source_guild"
+
+	StatusCurrent     Key = "status.current"
+	StatusEnabled     Key = "status.enabled"
+	StatusDisabled    Key = "status.disabled"
+	ListEmpty         Key = "list.empty"
+	ListMissingEntry  Key = "list.missing_entry"
+	ListAdded         Key = "list.added"
+	ListRemoved       Key = "list.removed"
+	ListChannelAllow  Key = "list.channel_allow"
+	ListChannelDeny   Key = "list.channel_deny"
+	ListRoleAllow     Key = "list.role_allow"
+	ListRoleDeny      Key = "list.role_deny"
+	LanguageCurrent   Key = "language.current"
+	LanguageSet       Key = "language.set"
+	LanguageInvalid   Key = "language.invalid"
+	LanguageDefault   Key = "language.default"
+	CasualHeader      Key = "casual.header"
+	CasualIgnored     Key = "casual.ignored"
+	CasualVotes       Key = "casual.votes"
+	CasualFunny       Key = "casual.funny"
+	CasualClever      Key = "casual.clever"
+	CasualDescriptive Key = "casual.descriptive"
+	CasualOther       Key = "casual.other"
+
+	PolicyMinVotes           Key = "policy.min_votes"
+	PolicyLockedOnly         Key = "policy.locked_only"
+	PolicyRequireNonOriginal Key = "policy.require_non_original"
+
+	PreferencesOptedOut    Key = "preferences.opted_out"
+	PreferencesOptedIn     Key = "preferences.opted_in"
+	PreferencesSetOptedOut Key = "preferences.set_opted_out"
+	PreferencesSetOptedIn  Key = "preferences.set_opted_in"
+
+	ShowOriginalButton      Key = "show_original.button"
+	ShowOriginalUnavailable Key = "show_original.unavailable"
+
+	EmbedOriginalTitle Key = "embed.original_title"
+	EmbedFooterTip     Key = "embed.footer_tip"
+	EmbedSpoiler       Key = "embed.spoiler"
+
+	ThumbnailModeRandomTime Key = "thumbnail_mode.random_time"
+	ThumbnailModeBlank      Key = "thumbnail_mode.blank"
+	ThumbnailModeOriginal   Key = "thumbnail_mode.original"
+
+	OriginalTitleModeShown  Key = "title_mode.shown"
+	OriginalTitleModeHidden Key = "title_mode.hidden"
+
+	LinkModeEmbeds  Key = "link_mode.embeds"
+	LinkModeContent Key = "link_mode.content"
+
+	TitleFormatNone                 Key = "title_format.none"
+	TitleFormatCapitalizeWords      Key = "title_format.capitalize_words"
+	TitleFormatTitleCase            Key = "title_format.title_case"
+	TitleFormatSentenceCase         Key = "title_format.sentence_case"
+	TitleFormatLowerCase            Key = "title_format.lower_case"
+	TitleFormatFirstLetterUppercase Key = "title_format.first_letter_uppercase"
+)
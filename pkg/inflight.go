@@ -0,0 +1,54 @@
+package pkg
+
+import (
+	"context"
+	"sync"
+)
+
+// Inflight keeps track of work in progress so that shutdown can wait for it to finish. Once closed, no new work is
+// accepted. It is safe for concurrent use.
+type Inflight struct {
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// Start marks the beginning of a unit of work. It returns false if no new work is accepted anymore. A successful start
+// must be followed by Done.
+func (i *Inflight) Start() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.closed {
+		return false
+	}
+	i.wg.Add(1)
+	return true
+}
+
+// Done marks the end of a unit of work.
+func (i *Inflight) Done() {
+	i.wg.Done()
+}
+
+// Close stops accepting new work. Work which has already started is unaffected.
+func (i *Inflight) Close() {
+	i.mu.Lock()
+	i.closed = true
+	i.mu.Unlock()
+}
+
+// Wait blocks until all started work is done or the context is done, in which case the context's error is returned.
+// It should only be called after Close, as new work could keep it waiting otherwise.
+func (i *Inflight) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		i.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,30 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInflightWait(t *testing.T) {
+	var inflight Inflight
+	if !inflight.Start() {
+		t.Fatal("expected work to be accepted")
+	}
+	inflight.Close()
+	if inflight.Start() {
+		t.Fatal("expected work to be rejected after closing")
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	if err := inflight.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the wait to time out, got %v", err)
+	}
+
+	time.AfterFunc(10*time.Millisecond, inflight.Done)
+	if err := inflight.Wait(t.Context()); err != nil {
+		t.Fatalf("expected the wait to finish, got %v", err)
+	}
+}
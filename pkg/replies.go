@@ -0,0 +1,102 @@
+package pkg
+
+import (
+	"context"
+	"dearrow-bot/pkg/db"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// ReplyTracker keeps track of DeArrow replies and makes sure that a parent message is only processed by one event at a time.
+// It is safe for concurrent use.
+type ReplyTracker struct {
+	store db.ReplyStore
+
+	mu      sync.Mutex
+	pending map[snowflake.ID]bool // value marks whether the parent has been deleted while being processed
+}
+
+func NewReplyTracker(store db.ReplyStore) *ReplyTracker {
+	return &ReplyTracker{
+		store:   store,
+		pending: make(map[snowflake.ID]bool),
+	}
+}
+
+// Acquire marks the parent message as being processed. It returns false if the message is already being processed.
+// A successful acquisition must be followed by either Commit or Release.
+func (t *ReplyTracker) Acquire(parentID snowflake.ID) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.pending[parentID]; ok {
+		return false
+	}
+	t.pending[parentID] = false
+	return true
+}
+
+// Reserve marks the parent message as being processed. It returns false if the message is already being processed
+// or if it has already been replied to. A successful reservation must be followed by either Commit or Release.
+func (t *ReplyTracker) Reserve(ctx context.Context, parentID snowflake.ID) (bool, error) {
+	if !t.Acquire(parentID) {
+		return false, nil
+	}
+	if _, ok, err := t.store.GetReply(ctx, parentID); err != nil || ok {
+		t.Release(parentID)
+		return false, err
+	}
+	return true, nil
+}
+
+// Release drops the reservation without storing a reply.
+func (t *ReplyTracker) Release(parentID snowflake.ID) {
+	t.mu.Lock()
+	delete(t.pending, parentID)
+	t.mu.Unlock()
+}
+
+// Commit creates or updates the reply and drops the reservation. It returns false if the parent message has been deleted
+// in the meantime, in which case the reply is not stored and should be deleted by the caller.
+func (t *ReplyTracker) Commit(ctx context.Context, parentID snowflake.ID, channelID snowflake.ID, reply db.Reply) (bool, error) {
+	t.mu.Lock()
+	deleted := t.pending[parentID]
+	t.mu.Unlock()
+	if deleted {
+		t.Release(parentID)
+		return false, nil
+	}
+	// the reservation is kept while saving, so that a concurrent Delete still marks the parent as deleted
+	err := t.store.SaveReply(ctx, parentID, channelID, reply)
+	t.mu.Lock()
+	deleted = t.pending[parentID]
+	delete(t.pending, parentID)
+	t.mu.Unlock()
+	if deleted { // the Delete may have happened before the save
+		_, _, err = t.store.DeleteReply(ctx, parentID)
+		return false, err
+	}
+	return true, err
+}
+
+// Get returns the reply to the parent message and whether it exists.
+func (t *ReplyTracker) Get(ctx context.Context, parentID snowflake.ID) (db.Reply, bool, error) {
+	return t.store.GetReply(ctx, parentID)
+}
+
+// Delete removes the reply to the parent message and returns its ID and whether it existed. If the parent message
+// is currently being processed, the pending reply will be reported as deleted on Commit.
+func (t *ReplyTracker) Delete(ctx context.Context, parentID snowflake.ID) (snowflake.ID, bool, error) {
+	t.mu.Lock()
+	if _, ok := t.pending[parentID]; ok {
+		t.pending[parentID] = true
+	}
+	t.mu.Unlock()
+	return t.store.DeleteReply(ctx, parentID)
+}
+
+// DeleteExpired removes all replies created before the provided time and returns how many were removed.
+func (t *ReplyTracker) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	return t.store.DeleteExpiredReplies(ctx, before)
+}
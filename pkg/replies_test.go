@@ -0,0 +1,177 @@
+package pkg
+
+import (
+	"context"
+	"dearrow-bot/pkg/db"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+func TestReplyTrackerReserveOnce(t *testing.T) {
+	tracker := NewReplyTracker(db.NewMemory())
+	const parentID = snowflake.ID(1)
+
+	var (
+		wg       sync.WaitGroup
+		reserved atomic.Int32
+	)
+	for range 64 {
+		wg.Go(func() {
+			ok, err := tracker.Reserve(t.Context(), parentID)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if ok {
+				reserved.Add(1)
+			}
+		})
+	}
+	wg.Wait()
+	if got := reserved.Load(); got != 1 {
+		t.Fatalf("expected exactly one reservation, got %d", got)
+	}
+}
+
+func TestReplyTrackerReserveAfterCommit(t *testing.T) {
+	tracker := NewReplyTracker(db.NewMemory())
+	const parentID, channelID, replyID = snowflake.ID(1), snowflake.ID(2), snowflake.ID(3)
+
+	if ok, _ := tracker.Reserve(t.Context(), parentID); !ok {
+		t.Fatal("expected the first reservation to succeed")
+	}
+	if ok, err := tracker.Commit(t.Context(), parentID, channelID, db.Reply{ID: replyID}); !ok || err != nil {
+		t.Fatalf("expected the commit to succeed, got %t, %v", ok, err)
+	}
+	if ok, _ := tracker.Reserve(t.Context(), parentID); ok {
+		t.Fatal("expected a reservation of a replied message to fail")
+	}
+	if r, ok, _ := tracker.Get(t.Context(), parentID); !ok || r.ID != replyID {
+		t.Fatalf("expected reply %d, got %d (%t)", replyID, r.ID, ok)
+	}
+}
+
+func TestReplyTrackerReserveAfterRelease(t *testing.T) {
+	tracker := NewReplyTracker(db.NewMemory())
+	const parentID = snowflake.ID(1)
+
+	if ok, _ := tracker.Reserve(t.Context(), parentID); !ok {
+		t.Fatal("expected the first reservation to succeed")
+	}
+	tracker.Release(parentID)
+	if ok, _ := tracker.Reserve(t.Context(), parentID); !ok {
+		t.Fatal("expected a reservation after release to succeed")
+	}
+}
+
+func TestReplyTrackerAcquireReplied(t *testing.T) {
+	tracker := NewReplyTracker(db.NewMemory())
+	const parentID, channelID = snowflake.ID(1), snowflake.ID(2)
+
+	tracker.Acquire(parentID)
+	if _, err := tracker.Commit(t.Context(), parentID, channelID, db.Reply{ID: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if !tracker.Acquire(parentID) {
+		t.Fatal("expected an acquisition of a replied message to succeed")
+	}
+	if tracker.Acquire(parentID) {
+		t.Fatal("expected a second acquisition to fail")
+	}
+	if _, err := tracker.Commit(t.Context(), parentID, channelID, db.Reply{ID: 4, VideoIDs: []string{"dQw4w9WgXcQ"}}); err != nil {
+		t.Fatal(err)
+	}
+	if r, _, _ := tracker.Get(t.Context(), parentID); r.ID != 4 || len(r.VideoIDs) != 1 {
+		t.Fatalf("expected the reply to be updated, got %+v", r)
+	}
+}
+
+func TestReplyTrackerDeleteWhilePending(t *testing.T) {
+	tracker := NewReplyTracker(db.NewMemory())
+	const parentID, channelID, replyID = snowflake.ID(1), snowflake.ID(2), snowflake.ID(3)
+
+	if ok, _ := tracker.Reserve(t.Context(), parentID); !ok {
+		t.Fatal("expected the first reservation to succeed")
+	}
+	if _, ok, _ := tracker.Delete(t.Context(), parentID); ok {
+		t.Fatal("expected no stored reply")
+	}
+	if ok, err := tracker.Commit(t.Context(), parentID, channelID, db.Reply{ID: replyID}); ok || err != nil {
+		t.Fatalf("expected the commit to report a deleted parent, got %t, %v", ok, err)
+	}
+	if _, ok, _ := tracker.Get(t.Context(), parentID); ok {
+		t.Fatal("expected the reply of a deleted parent not to be stored")
+	}
+}
+
+// hookedStore calls the hook after saving a reply.
+type hookedStore struct {
+	db.ReplyStore
+	afterSave func()
+}
+
+func (s *hookedStore) SaveReply(ctx context.Context, parentID snowflake.ID, channelID snowflake.ID, reply db.Reply) error {
+	err := s.ReplyStore.SaveReply(ctx, parentID, channelID, reply)
+	s.afterSave()
+	return err
+}
+
+func TestReplyTrackerDeleteWhileSaving(t *testing.T) {
+	const parentID, otherParentID, channelID, replyID = snowflake.ID(1), snowflake.ID(2), snowflake.ID(3), snowflake.ID(4)
+	store := &hookedStore{ReplyStore: db.NewMemory()}
+	tracker := NewReplyTracker(store)
+	store.afterSave = func() {
+		if !tracker.Acquire(otherParentID) { // other messages aren't blocked by the save
+			t.Error("expected another message to be acquired while saving")
+		}
+		if _, _, err := tracker.Delete(t.Context(), parentID); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if ok, _ := tracker.Reserve(t.Context(), parentID); !ok {
+		t.Fatal("expected the first reservation to succeed")
+	}
+	if ok, err := tracker.Commit(t.Context(), parentID, channelID, db.Reply{ID: replyID}); ok || err != nil {
+		t.Fatalf("expected the commit to report a deleted parent, got %t, %v", ok, err)
+	}
+	if _, ok, _ := tracker.Get(t.Context(), parentID); ok {
+		t.Fatal("expected the reply of a deleted parent not to be stored")
+	}
+}
+
+func TestReplyTrackerConcurrentAccess(t *testing.T) {
+	tracker := NewReplyTracker(db.NewMemory())
+
+	var wg sync.WaitGroup
+	for i := range 32 {
+		parentID := snowflake.ID(i % 8)
+		wg.Go(func() {
+			ok, err := tracker.Reserve(t.Context(), parentID)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if ok {
+				if _, err := tracker.Commit(t.Context(), parentID, 100, db.Reply{ID: parentID + 1000}); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+		wg.Go(func() {
+			if _, _, err := tracker.Delete(t.Context(), parentID); err != nil {
+				t.Error(err)
+			}
+		})
+		wg.Go(func() {
+			if _, err := tracker.DeleteExpired(t.Context(), time.Now().Add(-time.Hour)); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+}
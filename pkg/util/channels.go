@@ -0,0 +1,23 @@
+package util
+
+import (
+	"github.com/disgoorg/disgo/cache"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// ChannelChain returns the IDs of the channel and its parents, ordered from the most to the least specific one,
+// e.g. a thread, its channel and the category of the channel.
+func ChannelChain(caches cache.Caches, channelID snowflake.ID) []snowflake.ID {
+	chain := []snowflake.ID{channelID}
+	for {
+		channel, ok := caches.Channel(chain[len(chain)-1])
+		if !ok {
+			return chain
+		}
+		parentID := channel.ParentID()
+		if parentID == nil || *parentID == 0 {
+			return chain
+		}
+		chain = append(chain, *parentID)
+	}
+}
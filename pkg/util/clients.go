@@ -6,10 +6,6 @@ import (
 	"time"
 )
 
-var (
-	priorityKey = os.Getenv("DEARROW_PRIORITY_KEY")
-)
-
 const (
 	brandingTimeout  = 2 * time.Second // this is quite ambitious
 	thumbnailTimeout = 30 * time.Second
@@ -23,16 +19,23 @@ func NewBrandingClient() *http.Client {
 
 func NewThumbnailClient() *http.Client {
 	return &http.Client{
-		Timeout:   thumbnailTimeout,
-		Transport: &thumbnailTripper{tripper: http.DefaultTransport},
+		Timeout: thumbnailTimeout,
+		Transport: &thumbnailTripper{
+			tripper:     http.DefaultTransport,
+			priorityKey: os.Getenv("DEARROW_PRIORITY_KEY"),
+		},
 	}
 }
 
+// thumbnailTripper authorizes thumbnail requests with the priority key. As round trippers must not modify the
+// request, the key is set on a copy.
 type thumbnailTripper struct {
-	tripper http.RoundTripper
+	tripper     http.RoundTripper
+	priorityKey string
 }
 
 func (t *thumbnailTripper) RoundTrip(req *http.Request) (*http.Response, error) {
-	req.Header.Add("Authorization", priorityKey)
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", t.priorityKey)
 	return t.tripper.RoundTrip(req)
 }
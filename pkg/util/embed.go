@@ -1,12 +1,55 @@
 package util
 
 import (
-	"net/url"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/disgoorg/disgo/discord"
 )
 
+var (
+	urlRegex = regexp.MustCompile(`<?https?://[^\s<>|]+>?`)
+)
+
+// ContentLink is a video link found in message content.
+type ContentLink struct {
+	VideoID    string
+	URL        string
+	Suppressed bool // wrapped in <>, which prevents Discord from embedding it
+	Spoiler    bool // wrapped in ||
+}
+
+// ParseVideoID returns the ID of the YouTube video the embed points to, or an empty string if there's none.
 func ParseVideoID(embed discord.Embed) string {
-	u, _ := url.Parse(embed.URL)
-	return u.Query().Get("v")
+	return ParseVideoURL(embed.URL)
+}
+
+// ParseContentLinks returns all video links in the message content in order of appearance. Only the first link
+// of each video is returned, which is only suppressed or a spoiler if all of the video's links are, as Discord embeds
+// the video otherwise.
+func ParseContentLinks(content string) []ContentLink {
+	var links []ContentLink
+	for _, match := range urlRegex.FindAllStringIndex(content, -1) {
+		link := content[match[0]:match[1]]
+		suppressed := strings.HasPrefix(link, "<") && strings.HasSuffix(link, ">")
+		spoiler := strings.Count(content[:match[0]], "||")%2 == 1       // inside an unclosed spoiler
+		link = strings.TrimRight(strings.Trim(link, "<>"), `.,:;!?)'"`) // punctuation after a link isn't part of it
+		videoID := ParseVideoURL(link)
+		if videoID == "" {
+			continue
+		}
+		if i := slices.IndexFunc(links, func(l ContentLink) bool { return l.VideoID == videoID }); i != -1 {
+			links[i].Suppressed = links[i].Suppressed && suppressed
+			links[i].Spoiler = links[i].Spoiler && spoiler
+			continue
+		}
+		links = append(links, ContentLink{
+			VideoID:    videoID,
+			URL:        link,
+			Suppressed: suppressed,
+			Spoiler:    spoiler,
+		})
+	}
+	return links
 }
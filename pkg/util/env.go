@@ -0,0 +1,36 @@
+package util
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+)
+
+// GetEnvInt returns the integer value of the environment variable, or the fallback if it's unset or invalid.
+func GetEnvInt(key string, fallback int) int {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		slog.Warn("dearrow: invalid integer in environment variable", slog.String("key", key), slog.String("value", value))
+		return fallback
+	}
+	return i
+}
+
+// GetEnvDuration returns the duration value of the environment variable, or the fallback if it's unset or invalid.
+func GetEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		slog.Warn("dearrow: invalid duration in environment variable", slog.String("key", key), slog.String("value", value))
+		return fallback
+	}
+	return d
+}
@@ -0,0 +1,130 @@
+package util
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	videoIDRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]{11}$`)
+	// pageRegex matches the names of front-end pages, e.g. /preferences, which can't be told apart from video IDs by
+	// their length. Video IDs are random, so hardly any are made up of lowercase letters only.
+	pageRegex = regexp.MustCompile(`^[a-z_-]+$`)
+)
+
+var (
+	youtubeHosts = map[string]struct{}{
+		"youtube.com":              {},
+		"www.youtube.com":          {},
+		"m.youtube.com":            {},
+		"music.youtube.com":        {},
+		"youtube-nocookie.com":     {},
+		"www.youtube-nocookie.com": {},
+	}
+	shortHosts = map[string]struct{}{
+		"youtu.be":     {},
+		"www.youtu.be": {},
+	}
+	// frontendHosts are hosts of alternative YouTube front-ends such as Piped and Invidious. They use the same
+	// video IDs and URL shapes as YouTube, and additionally accept the video ID as the whole path.
+	frontendHosts = map[string]struct{}{
+		"piped.video":                   {},
+		"piped.private.coffee":          {},
+		"yewtu.be":                      {},
+		"inv.nadeko.net":                {},
+		"invidious.nerdvpn.de":          {},
+		"invidious.privacyredirect.com": {},
+	}
+	// videoPathPrefixes are path prefixes followed by the video ID, e.g. /shorts/dQw4w9WgXcQ.
+	videoPathPrefixes = []string{"/shorts/", "/live/", "/embed/", "/v/", "/e/"}
+)
+
+// RegisterFrontendHosts adds hosts of alternative YouTube front-ends to the ones recognized by default.
+// It must be called before any URLs are parsed.
+func RegisterFrontendHosts(hosts ...string) {
+	for _, host := range hosts {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			frontendHosts[host] = struct{}{}
+		}
+	}
+}
+
+// ParseVideoURL returns the ID of the video the YouTube or front-end URL points to, or an empty string if it isn't
+// a valid video URL. The scheme may be omitted.
+func ParseVideoURL(rawURL string) string {
+	u := parseURL(rawURL)
+	if u == nil {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	var videoID string
+	if _, ok := shortHosts[host]; ok {
+		videoID = parseShortPath(u)
+	} else if _, ok := youtubeHosts[host]; ok {
+		videoID = parseVideoPath(u)
+	} else if _, ok := frontendHosts[host]; ok {
+		if videoID = parseVideoPath(u); videoID == "" {
+			videoID = parseFrontendShortPath(u)
+		}
+	}
+	if !IsVideoID(videoID) {
+		return ""
+	}
+	return videoID
+}
+
+// IsFrontendURL checks whether the URL points to an alternative YouTube front-end.
+func IsFrontendURL(rawURL string) bool {
+	u := parseURL(rawURL)
+	if u == nil {
+		return false
+	}
+	_, ok := frontendHosts[strings.ToLower(u.Hostname())]
+	return ok
+}
+
+// IsVideoID checks whether the string has the format of a YouTube video ID.
+func IsVideoID(s string) bool {
+	return videoIDRegex.MatchString(s)
+}
+
+func parseURL(rawURL string) *url.URL {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil
+	}
+	return u
+}
+
+func parseShortPath(u *url.URL) string {
+	videoID, _, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	return videoID
+}
+
+// parseFrontendShortPath returns the video ID of front-end URLs whose whole path is the ID, e.g. /dQw4w9WgXcQ.
+// Front-ends serve their own pages next to these, which are skipped.
+func parseFrontendShortPath(u *url.URL) string {
+	videoID := strings.TrimSuffix(strings.TrimPrefix(u.Path, "/"), "/")
+	if strings.Contains(videoID, "/") || pageRegex.MatchString(videoID) {
+		return ""
+	}
+	return videoID
+}
+
+func parseVideoPath(u *url.URL) string {
+	path := strings.TrimSuffix(u.Path, "/")
+	if path == "/watch" {
+		return u.Query().Get("v")
+	}
+	for _, prefix := range videoPathPrefixes {
+		if rest, ok := strings.CutPrefix(path, prefix); ok {
+			videoID, _, _ := strings.Cut(rest, "/")
+			return videoID
+		}
+	}
+	return ""
+}
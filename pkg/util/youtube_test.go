@@ -0,0 +1,110 @@
+package util
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseVideoURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		videoID string
+	}{
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://youtube.com/watch?v=dQw4w9WgXcQ&t=42s", "dQw4w9WgXcQ"},
+		{"http://m.youtube.com/watch?feature=share&v=dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://music.youtube.com/watch?v=dQw4w9WgXcQ&list=RDAMVM", "dQw4w9WgXcQ"},
+		{"https://WWW.YouTube.com/watch?v=dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"www.youtube.com/watch?v=dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://www.youtube.com/shorts/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://youtube.com/shorts/dQw4w9WgXcQ?feature=share", "dQw4w9WgXcQ"},
+		{"https://www.youtube.com/live/dQw4w9WgXcQ?si=abc", "dQw4w9WgXcQ"},
+		{"https://www.youtube.com/embed/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ?start=10", "dQw4w9WgXcQ"},
+		{"https://www.youtube.com/v/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://youtu.be/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://youtu.be/dQw4w9WgXcQ?t=42", "dQw4w9WgXcQ"},
+		{"https://www.youtube.com/watch/?v=dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://www.youtube.com/watch?v=dQw4w9WgXc", ""},
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQQ", ""},
+		{"https://www.youtube.com/watch?v=dQw4w9WgXc!", ""},
+		{"https://www.youtube.com/watch", ""},
+		{"https://www.youtube.com/channel/UCuAXFkgsw1L7xaCfnd5JJOw", ""},
+		{"https://www.youtube.com/playlist?list=PLFgquLnL59alCl_2TQvOiD5Vgm1hCaGSI", ""},
+		{"https://youtu.be/", ""},
+		{"https://piped.video/watch?v=dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://yewtu.be/watch?v=dQw4w9WgXcQ&listen=false", "dQw4w9WgXcQ"},
+		{"https://yewtu.be/shorts/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://piped.video/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://piped.video/trending", ""},
+		{"https://yewtu.be/preferences", ""},
+		{"https://piped.video/preferences/", ""},
+		{"https://yewtu.be/data_export", ""},
+		{"https://yewtu.be/dQw4w9WgXcQ/comments", ""},
+		{"https://example.com/watch?v=dQw4w9WgXcQ", ""},
+		{"https://notyoutube.com/watch?v=dQw4w9WgXcQ", ""},
+		{"ftp://youtube.com/watch?v=dQw4w9WgXcQ", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if videoID := ParseVideoURL(tt.url); videoID != tt.videoID {
+				t.Errorf("expected %q, got %q", tt.videoID, videoID)
+			}
+		})
+	}
+}
+
+func TestRegisterFrontendHosts(t *testing.T) {
+	const link = "https://invidious.example.org/watch?v=dQw4w9WgXcQ"
+	if ParseVideoURL(link) != "" || IsFrontendURL(link) {
+		t.Fatal("expected an unregistered host not to be recognized")
+	}
+	RegisterFrontendHosts(" Invidious.Example.org ")
+	t.Cleanup(func() {
+		delete(frontendHosts, "invidious.example.org")
+	})
+	if videoID := ParseVideoURL(link); videoID != "dQw4w9WgXcQ" {
+		t.Fatalf("expected the video ID of a registered host, got %q", videoID)
+	}
+	if !IsFrontendURL(link) {
+		t.Fatal("expected a registered host to be a front-end")
+	}
+	if IsFrontendURL("https://youtu.be/dQw4w9WgXcQ") {
+		t.Fatal("expected YouTube not to be a front-end")
+	}
+}
+
+func TestParseContentLinks(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected []ContentLink
+	}{
+		{
+			content: "first <https://youtu.be/dQw4w9WgXcQ> then ||https://www.youtube.com/shorts/jNQXAC9IVRw|| and <https://youtu.be/dQw4w9WgXcQ> again",
+			expected: []ContentLink{
+				{VideoID: "dQw4w9WgXcQ", URL: "https://youtu.be/dQw4w9WgXcQ", Suppressed: true},
+				{VideoID: "jNQXAC9IVRw", URL: "https://www.youtube.com/shorts/jNQXAC9IVRw", Spoiler: true},
+			},
+		},
+		{
+			// Discord embeds the videos as they're linked again without <> and ||
+			content: "first <https://youtu.be/dQw4w9WgXcQ> then ||https://www.youtube.com/shorts/jNQXAC9IVRw|| and https://youtu.be/dQw4w9WgXcQ https://youtu.be/jNQXAC9IVRw again",
+			expected: []ContentLink{
+				{VideoID: "dQw4w9WgXcQ", URL: "https://youtu.be/dQw4w9WgXcQ"},
+				{VideoID: "jNQXAC9IVRw", URL: "https://www.youtube.com/shorts/jNQXAC9IVRw"},
+			},
+		},
+		{
+			content: "look at this (https://youtu.be/dQw4w9WgXcQ). https://example.com/watch?v=jNQXAC9IVRw",
+			expected: []ContentLink{
+				{VideoID: "dQw4w9WgXcQ", URL: "https://youtu.be/dQw4w9WgXcQ"},
+			},
+		},
+	}
+	for _, test := range tests {
+		if links := ParseContentLinks(test.content); !slices.Equal(links, test.expected) {
+			t.Errorf("%q: expected %+v, got %+v", test.content, test.expected, links)
+		}
+	}
+}